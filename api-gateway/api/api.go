@@ -3,21 +3,35 @@ package api
 import (
 	"api-gateway/middleware"
 	"api-gateway/service"
+	"api-gateway/util/apikey"
+	"api-gateway/util/audit"
+	"api-gateway/util/jwt"
+	"api-gateway/util/ratelimit"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type Api struct {
 	serviceName string
+	adminTokens []string
 
-	service *service.Service
+	service     *service.Service
+	auditLogger *audit.Logger
+	jwtVerifier *jwt.Verifier // nil when JWT auth is disabled
+	apiKeys     *apikey.Store // nil when API keys are disabled
+	rateLimiter *ratelimit.Limiter
 }
 
-func NewApi(serviceName string, service *service.Service) *Api {
+func NewApi(serviceName string, adminTokens []string, service *service.Service, auditLogger *audit.Logger, jwtVerifier *jwt.Verifier, apiKeys *apikey.Store, rateLimiter *ratelimit.Limiter) *Api {
 	return &Api{
 		serviceName: serviceName,
+		adminTokens: adminTokens,
 
-		service: service,
+		service:     service,
+		auditLogger: auditLogger,
+		jwtVerifier: jwtVerifier,
+		apiKeys:     apiKeys,
+		rateLimiter: rateLimiter,
 	}
 }
 
@@ -31,29 +45,98 @@ func (api *Api) DefineEndpoints(app *fiber.App) *fiber.App {
 	// Get all available services
 	discovery.Get("/services", api.getAllServices)
 
+	// Instances of a service with their health checks
+	discovery.Get("/services/:serviceName", api.getService)
+	discovery.Get("/services/:serviceName/checks", api.getServiceChecks)
+
 	// Ping all available services
 	discovery.Get("/ping-all", api.pingAllServices)
 
+	// Consul cluster and node inventory (JSON, or ?format=table)
+	discovery.Get("/cluster", api.getCluster)
+	discovery.Get("/nodes", api.getNodes)
+
+	// Rolling latency statistics per service and instance
+	discovery.Get("/latency", api.getLatency)
+
+	// Uptime and incidents recorded by the background prober
+	discovery.Get("/uptime", api.getUptime)
+
+	// Stream of catalog and health changes (SSE or NDJSON)
+	discovery.Get("/watch", api.watchCatalog)
+
 	// Generic Service Routing
 	// This is the main feature - dynamic routing to any service!
 	routes := app.Group("/api")
 
+	// Authentication for the routed endpoints (pass-through when disabled)
+	// With both enabled, requests carrying X-API-Key use the key and all others need a JWT
+	authenticate := func(c *fiber.Ctx) error { return c.Next() }
+	if api.jwtVerifier != nil {
+		authenticate = middleware.JWTAuth(api.jwtVerifier, api.service.GetSettings)
+	}
+	if api.apiKeys != nil {
+		var fallback fiber.Handler
+		if api.jwtVerifier != nil {
+			fallback = authenticate
+		}
+		authenticate = middleware.APIKeyAuth(api.apiKeys, api.service.GetSettings, fallback)
+	}
+
+	// Rate limits from the runtime settings, per consumer (after authentication)
+	rateLimit := middleware.RateLimit(api.rateLimiter, api.service.GetSettings)
+
 	// Generic ping endpoint - routes to any service dynamically
 	// Usage: GET /api/ping/{service-name}
 	// Examples:
 	//   GET /api/ping/service-a  -> discovers and pings service-a
 	//   GET /api/ping/service-b  -> discovers and pings service-b
 	//   GET /api/ping/service-c  -> discovers and pings service-c (when it exists)
-	routes.Get("/ping/:serviceName", api.pingService)
-
-	// Health check for the gateway itself
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"service": api.serviceName,
-			"status":  "healthy",
-			"message": "API Gateway is running",
-		})
-	})
+	//   GET /api/ping/service-a?all=true  -> pings every instance of service-a in parallel
+	routes.Get("/ping/:serviceName", authenticate, rateLimit, api.pingService)
+
+	// Ping one specific instance, bypassing the load balancer
+	// Usage: GET /api/ping/{service-name}/{instance-id}
+	routes.Get("/ping/:serviceName/:instanceID", authenticate, rateLimit, api.pingServiceInstance)
+
+	// Admin Routes
+	// Every admin action requires an admin token and is recorded in the audit log
+	admin := app.Group("/admin", middleware.AdminAuth(api.adminTokens))
+
+	// Drain / restore a service instance via Consul maintenance mode
+	admin.Put("/instances/:instanceID/maintenance", api.enableMaintenance)
+	admin.Delete("/instances/:instanceID/maintenance", api.disableMaintenance)
+
+	// Force-deregister a stale service instance from the catalog
+	admin.Delete("/instances/:instanceID", api.deregisterInstance)
+
+	// Runtime settings currently in effect
+	admin.Get("/settings", api.getSettings)
+
+	// Recent admin actions
+	admin.Get("/audit", api.getAuditLog)
+
+	// API key management (keys are stored hashed in Consul KV)
+	admin.Get("/api-keys", api.listAPIKeys)
+	admin.Post("/api-keys", api.createAPIKey)
+	admin.Post("/api-keys/:keyID/rotate", api.rotateAPIKey)
+	admin.Delete("/api-keys/:keyID", api.revokeAPIKey)
+
+	// Consul prepared queries, which routes can target instead of a service
+	admin.Get("/prepared-queries", api.listPreparedQueries)
+	admin.Post("/prepared-queries", api.createPreparedQuery)
+	admin.Get("/prepared-queries/:queryID", api.getPreparedQuery)
+	admin.Put("/prepared-queries/:queryID", api.updatePreparedQuery)
+	admin.Delete("/prepared-queries/:queryID", api.deletePreparedQuery)
+
+	// Metrics in the Prometheus text format
+	app.Get("/metrics", api.getMetrics)
+
+	// Health checks for the gateway itself
+	// Liveness: the process is up; readiness: the gateway can route (503 otherwise)
+	app.Get("/health/live", api.getLiveness)
+	app.Get("/health/ready", api.getReadiness)
+	app.Get("/health", api.getReadiness) // kept for existing scripts
 
 	return app
 }
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// apiKeyErrorStatus maps API key management errors to HTTP status codes
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidAPIKeyRequest):
+		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrAPIKeysDisabled), errors.Is(err, service.ErrAPIKeyNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrAPIKeyConflict), errors.Is(err, service.ErrAPIKeyRevoked):
+		return fiber.StatusConflict
+	}
+
+	return fiber.StatusInternalServerError
+}
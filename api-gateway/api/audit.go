@@ -0,0 +1,27 @@
+package api
+
+import (
+	"api-gateway/middleware"
+	"api-gateway/util/audit"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// audit records an admin action performed through the current request
+func (api *Api) audit(c *fiber.Ctx, action, target, reason string, err error) {
+	actor, _ := c.Locals(middleware.AdminActorKey).(string)
+
+	entry := audit.Entry{
+		Actor:      actor,
+		Action:     action,
+		Target:     target,
+		Reason:     reason,
+		RemoteAddr: c.IP(),
+		Success:    err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	api.auditLogger.Record(entry)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"api-gateway/service"
+	"api-gateway/util/settings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type createAPIKeyRequest struct {
+	Owner     string            `json:"owner"`
+	Services  []string          `json:"services"`
+	ExpiresAt *time.Time        `json:"expires_at"` // RFC 3339
+	ExpiresIn settings.Duration `json:"expires_in"` // e.g. "720h", alternative to expires_at
+}
+
+// createAPIKey creates an API key; the plain key is only returned by this call
+func (api *Api) createAPIKey(c *fiber.Ctx) error {
+	var request createAPIKeyRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "invalid request body",
+			"details": err.Error(),
+			"usage":   `POST /admin/api-keys {"owner": "partner-x", "services": ["service-a"], "expires_in": "720h"}`,
+		})
+	}
+
+	if request.ExpiresAt == nil && request.ExpiresIn > 0 {
+		expiresAt := time.Now().UTC().Add(time.Duration(request.ExpiresIn))
+		request.ExpiresAt = &expiresAt
+	}
+
+	response, err := api.service.CreateAPIKey(&service.CreateAPIKeyParam{
+		Owner:     request.Owner,
+		Services:  request.Services,
+		ExpiresAt: request.ExpiresAt,
+	})
+
+	target := request.Owner
+	if response != nil {
+		target = response.Key.ID
+	}
+	api.audit(c, "create_api_key", target, "owner="+request.Owner+" services="+strings.Join(request.Services, ","), err)
+
+	if err != nil {
+		return c.Status(apiKeyErrorStatus(err)).JSON(fiber.Map{
+			"error":   "failed to create API key",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"key":     response.Key,
+		"token":   response.Token,
+		"message": "API key created, store the token now: it cannot be shown again",
+	})
+}
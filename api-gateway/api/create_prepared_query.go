@@ -0,0 +1,39 @@
+package api
+
+import (
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// createPreparedQuery creates a prepared query; the body is a Consul prepared query definition
+func (api *Api) createPreparedQuery(c *fiber.Ctx) error {
+	var request service.PreparedQuery
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "invalid request body",
+			"details": err.Error(),
+			"usage":   "POST /admin/prepared-queries " + preparedQueryUsage,
+		})
+	}
+
+	query, err := api.service.CreatePreparedQuery(&service.CreatePreparedQueryParam{Query: &request})
+
+	target := request.Name
+	if query != nil {
+		target = query.ID
+	}
+	api.audit(c, "create_prepared_query", target, "name="+request.Name+" service="+request.Service.Service, err)
+
+	if err != nil {
+		return c.Status(preparedQueryErrorStatus(err)).JSON(fiber.Map{
+			"error":   "failed to create prepared query",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"query":   query,
+		"message": "Prepared query created",
+	})
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// deletePreparedQuery deletes a prepared query
+func (api *Api) deletePreparedQuery(c *fiber.Ctx) error {
+	queryID := c.Params("queryID")
+
+	err := api.service.DeletePreparedQuery(&service.DeletePreparedQueryParam{QueryID: queryID})
+	api.audit(c, "delete_prepared_query", queryID, c.Query("reason"), err)
+	if err != nil {
+		return c.Status(preparedQueryErrorStatus(err)).JSON(fiber.Map{
+			"error":    "failed to delete prepared query",
+			"query_id": queryID,
+			"details":  err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"query_id": queryID,
+		"message":  "Prepared query deleted",
+	})
+}
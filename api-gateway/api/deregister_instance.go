@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// deregisterInstance force-removes a stale service instance from the Consul catalog
+func (api *Api) deregisterInstance(c *fiber.Ctx) error {
+	instanceID := c.Params("instanceID")
+	reason := c.Query("reason")
+
+	instance, err := api.service.DeregisterInstance(&service.DeregisterInstanceParam{InstanceID: instanceID})
+	api.audit(c, "deregister_instance", instanceID, reason, err)
+	if err != nil {
+		status := 500
+		if errors.Is(err, service.ErrInstanceNotFound) {
+			status = 404
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error":    "failed to deregister instance",
+			"instance": instanceID,
+			"details":  err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"instance": instance,
+		"message":  "Instance deregistered from Consul catalog",
+	})
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// disableMaintenance takes a service instance out of Consul maintenance mode
+func (api *Api) disableMaintenance(c *fiber.Ctx) error {
+	instanceID := c.Params("instanceID")
+
+	err := api.service.DisableMaintenance(&service.DisableMaintenanceParam{InstanceID: instanceID})
+	api.audit(c, "disable_maintenance", instanceID, "", err)
+	if err != nil {
+		status := 500
+		if errors.Is(err, service.ErrInstanceNotFound) {
+			status = 404
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error":    "failed to disable maintenance",
+			"instance": instanceID,
+			"details":  err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"instance": instanceID,
+		"message":  "Instance is no longer in maintenance mode",
+	})
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type enableMaintenanceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// enableMaintenance puts a service instance into Consul maintenance mode
+func (api *Api) enableMaintenance(c *fiber.Ctx) error {
+	instanceID := c.Params("instanceID")
+
+	// The reason can be sent as JSON body or as ?reason= query parameter
+	request := enableMaintenanceRequest{Reason: c.Query("reason")}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+		}
+	}
+
+	if request.Reason == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "reason is required",
+			"usage": `PUT /admin/instances/{instance-id}/maintenance {"reason": "..."}`,
+		})
+	}
+
+	err := api.service.EnableMaintenance(&service.EnableMaintenanceParam{
+		InstanceID: instanceID,
+		Reason:     request.Reason,
+	})
+	api.audit(c, "enable_maintenance", instanceID, request.Reason, err)
+	if err != nil {
+		status := 500
+		if errors.Is(err, service.ErrInstanceNotFound) {
+			status = 404
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error":    "failed to enable maintenance",
+			"instance": instanceID,
+			"details":  err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"instance": instanceID,
+		"reason":   request.Reason,
+		"message":  "Instance is now in maintenance mode",
+	})
+}
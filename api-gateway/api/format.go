@@ -0,0 +1,48 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"api-gateway/util/table"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Response formats of the inventory endpoints
+const (
+	formatJSON  = "json"
+	formatTable = "table"
+)
+
+// responseFormat returns the format requested with ?format=json|table,
+// or table when the client only accepts text/plain (e.g. curl -H "Accept: text/plain")
+func responseFormat(c *fiber.Ctx) (string, error) {
+	switch format := c.Query("format"); format {
+	case formatJSON, formatTable:
+		return format, nil
+	case "":
+		if strings.HasPrefix(c.Get(fiber.HeaderAccept), fiber.MIMETextPlain) {
+			return formatTable, nil
+		}
+		return formatJSON, nil
+	default:
+		return "", fmt.Errorf("format must be %s or %s, got %q", formatJSON, formatTable, format)
+	}
+}
+
+// textTable renders rows as an aligned text table
+func textTable(header []string, rows [][]string) string {
+	var text bytes.Buffer
+	table.Write(&text, header, rows)
+
+	return text.String()
+}
+
+// sendText sends a plain text response
+func sendText(c *fiber.Ctx, text string) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+
+	return c.SendString(text)
+}
@@ -1,22 +1,39 @@
 package api
 
 import (
+	"errors"
+
+	"api-gateway/service"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 // getAllServices returns all services available in Consul
+// Optional query parameter: ?dc=dc2 for another datacenter than the local one
 func (api *Api) getAllServices(c *fiber.Ctx) error {
-	services, err := api.service.GetAllAvailableServices()
+	datacenter := c.Query("dc")
+
+	services, err := api.service.GetAllAvailableServices(&service.GetAllServicesParam{Datacenter: datacenter})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
+		status := 500
+		if errors.Is(err, service.ErrUnknownDatacenter) {
+			status = 404
+		}
+
+		return c.Status(status).JSON(fiber.Map{
 			"error":   "failed to get services",
 			"details": err.Error(),
 		})
 	}
 
-	return c.JSON(fiber.Map{
+	response := fiber.Map{
 		"services": services,
 		"count":    len(services),
 		"message":  "Available services in Consul registry",
-	})
+	}
+	if datacenter != "" {
+		response["datacenter"] = datacenter
+	}
+
+	return c.JSON(response)
 }
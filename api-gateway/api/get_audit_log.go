@@ -0,0 +1,16 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// getAuditLog returns the most recent admin actions
+func (api *Api) getAuditLog(c *fiber.Ctx) error {
+	entries := api.auditLogger.Recent(c.QueryInt("limit", 50))
+
+	return c.JSON(fiber.Map{
+		"entries": entries,
+		"count":   len(entries),
+		"message": "Most recent admin actions",
+	})
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getCluster returns the Raft leader and peers, agent members and datacenter of the Consul cluster
+// Optional query parameter: ?format=table for plain text
+func (api *Api) getCluster(c *fiber.Ctx) error {
+	format, err := responseFormat(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "invalid format",
+			"details": err.Error(),
+		})
+	}
+
+	cluster, err := api.service.GetCluster()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "failed to get cluster state",
+			"details": err.Error(),
+		})
+	}
+
+	if format == formatJSON {
+		return c.JSON(fiber.Map{
+			"cluster": cluster,
+			"message": "Consul cluster state",
+		})
+	}
+
+	leader := cluster.Leader
+	if leader == "" {
+		leader = "(none)"
+	}
+
+	rows := make([][]string, 0, len(cluster.Members))
+	for _, member := range cluster.Members {
+		rows = append(rows, []string{
+			member.Name,
+			fmt.Sprintf("%s:%d", member.Address, member.Port),
+			member.Status,
+			member.Role,
+			member.Datacenter,
+			member.Version,
+		})
+	}
+
+	return sendText(c, fmt.Sprintf("Datacenter: %s\nAgent:      %s\nLeader:     %s\nPeers:      %s\n\n%s",
+		cluster.Datacenter, cluster.Agent, leader, strings.Join(cluster.Peers, ", "),
+		textTable([]string{"MEMBER", "ADDRESS", "STATUS", "ROLE", "DC", "VERSION"}, rows)))
+}
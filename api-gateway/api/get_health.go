@@ -0,0 +1,34 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// getLiveness reports that the gateway process is up and serving requests
+func (api *Api) getLiveness(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"service": api.serviceName,
+		"status":  "alive",
+		"message": "API Gateway is running",
+	})
+}
+
+// getReadiness reports whether the gateway can route requests, with the result of every check
+// It responds with 503 when the gateway cannot route
+func (api *Api) getReadiness(c *fiber.Ctx) error {
+	report := api.service.CheckReadiness()
+
+	status := "ready"
+	code := fiber.StatusOK
+	if !report.Ready {
+		status = "not ready"
+		code = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(code).JSON(fiber.Map{
+		"service": api.serviceName,
+		"status":  status,
+		"health":  report.Status,
+		"checks":  report.Checks,
+	})
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"time"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getLatency returns rolling latency statistics per service and instance
+// Optional query parameters: ?window=1m (default and max 5m) and ?service=service-a
+func (api *Api) getLatency(c *fiber.Ctx) error {
+	var window time.Duration
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "invalid window",
+				"usage": "GET /discovery/latency?window=1m&service=service-a",
+			})
+		}
+		window = parsed
+	}
+
+	response := api.service.GetLatencyStats(&service.GetLatencyStatsParam{
+		Window:      window,
+		ServiceName: c.Query("service"),
+	})
+
+	return c.JSON(fiber.Map{
+		"window":   response.Window,
+		"services": response.Services,
+		"count":    len(response.Services),
+		"message":  "Latency of requests to services, from proxied traffic and pings",
+	})
+}
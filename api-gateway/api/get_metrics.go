@@ -0,0 +1,15 @@
+package api
+
+import (
+	"api-gateway/util/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getMetrics exposes the gateway metrics in the Prometheus text format
+func (api *Api) getMetrics(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	metrics.Write(c)
+
+	return nil
+}
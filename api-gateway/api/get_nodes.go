@@ -0,0 +1,59 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getNodes returns every Consul node with its services and node-level health checks
+// Optional query parameter: ?format=table for plain text
+func (api *Api) getNodes(c *fiber.Ctx) error {
+	format, err := responseFormat(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "invalid format",
+			"details": err.Error(),
+		})
+	}
+
+	nodes, err := api.service.GetNodes()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "failed to get nodes",
+			"details": err.Error(),
+		})
+	}
+
+	if format == formatJSON {
+		return c.JSON(fiber.Map{
+			"nodes":   nodes,
+			"count":   len(nodes),
+			"message": "Nodes in the Consul catalog",
+		})
+	}
+
+	rows := make([][]string, 0, len(nodes))
+	for _, node := range nodes {
+		services := make([]string, 0, len(node.Services))
+		for _, service := range node.Services {
+			services = append(services, service.ID)
+		}
+
+		checks := make([]string, 0, len(node.Checks))
+		for _, check := range node.Checks {
+			checks = append(checks, check.CheckID+"="+check.Status)
+		}
+
+		rows = append(rows, []string{
+			node.Name,
+			node.Address,
+			node.Datacenter,
+			node.Status,
+			strings.Join(services, ","),
+			strings.Join(checks, ","),
+		})
+	}
+
+	return sendText(c, textTable([]string{"NODE", "ADDRESS", "DC", "STATUS", "SERVICES", "CHECKS"}, rows))
+}
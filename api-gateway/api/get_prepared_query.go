@@ -0,0 +1,25 @@
+package api
+
+import (
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getPreparedQuery returns a prepared query definition
+func (api *Api) getPreparedQuery(c *fiber.Ctx) error {
+	queryID := c.Params("queryID")
+
+	query, err := api.service.GetPreparedQuery(&service.GetPreparedQueryParam{QueryID: queryID})
+	if err != nil {
+		return c.Status(preparedQueryErrorStatus(err)).JSON(fiber.Map{
+			"error":    "failed to get prepared query",
+			"query_id": queryID,
+			"details":  err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"query": query,
+	})
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getService returns every registered instance of a service with its address, tags, meta, weights and node
+func (api *Api) getService(c *fiber.Ctx) error {
+	serviceName := c.Params("serviceName")
+
+	details, err := api.service.GetService(&service.GetServiceParam{ServiceName: serviceName})
+	if err != nil {
+		status := 500
+		if errors.Is(err, service.ErrServiceNotFound) {
+			status = 404
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error":   "failed to get service",
+			"service": serviceName,
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"service":   details.Service,
+		"instances": details.Instances,
+		"count":     len(details.Instances),
+		"message":   "Registered instances of " + details.Service,
+	})
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getServiceChecks returns the Consul health checks of every instance of a service
+func (api *Api) getServiceChecks(c *fiber.Ctx) error {
+	serviceName := c.Params("serviceName")
+
+	checks, err := api.service.GetServiceChecks(&service.GetServiceChecksParam{ServiceName: serviceName})
+	if err != nil {
+		status := 500
+		if errors.Is(err, service.ErrServiceNotFound) {
+			status = 404
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error":   "failed to get health checks",
+			"service": serviceName,
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"service": checks.Service,
+		"checks":  checks.Checks,
+		"count":   len(checks.Checks),
+		"message": "Health checks of " + checks.Service,
+	})
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// getSettings returns the runtime settings currently in effect
+func (api *Api) getSettings(c *fiber.Ctx) error {
+	current := api.service.GetSettings()
+
+	return c.JSON(fiber.Map{
+		"settings": current,
+		"revision": current.Revision,
+		"message":  "Gateway settings currently in effect",
+	})
+}
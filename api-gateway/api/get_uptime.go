@@ -0,0 +1,50 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getUptime returns the uptime percentage and incident timeline of services
+// Optional query parameters: ?window=24h (default and max the retention) and ?service=service-a
+func (api *Api) getUptime(c *fiber.Ctx) error {
+	var window time.Duration
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "invalid window",
+				"usage": "GET /discovery/uptime?window=24h&service=service-a",
+			})
+		}
+		window = parsed
+	}
+
+	response, err := api.service.GetUptime(&service.GetUptimeParam{
+		Window:      window,
+		ServiceName: c.Query("service"),
+	})
+	if errors.Is(err, service.ErrProberDisabled) {
+		return c.Status(404).JSON(fiber.Map{
+			"error":   "Uptime is not recorded",
+			"details": "Enable the prober (prober.enabled) to record uptime",
+		})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to get uptime",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"window":   response.Window,
+		"services": response.Services,
+		"count":    len(response.Services),
+		"message":  "Uptime of services from background probes",
+	})
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// listAPIKeys returns every API key known to the gateway (never the keys themselves)
+func (api *Api) listAPIKeys(c *fiber.Ctx) error {
+	keys, err := api.service.ListAPIKeys()
+	if err != nil {
+		return c.Status(apiKeyErrorStatus(err)).JSON(fiber.Map{
+			"error":   "failed to list API keys",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
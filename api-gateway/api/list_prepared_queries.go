@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// listPreparedQueries returns every prepared query defined in Consul
+func (api *Api) listPreparedQueries(c *fiber.Ctx) error {
+	queries, err := api.service.ListPreparedQueries()
+	if err != nil {
+		return c.Status(preparedQueryErrorStatus(err)).JSON(fiber.Map{
+			"error":   "failed to list prepared queries",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"queries": queries,
+		"count":   len(queries),
+	})
+}
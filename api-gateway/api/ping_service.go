@@ -1,6 +1,7 @@
 package api
 
 import (
+	"api-gateway/middleware"
 	"api-gateway/service"
 
 	"github.com/gofiber/fiber/v2"
@@ -8,6 +9,7 @@ import (
 
 // pingService is the core dynamic routing function
 // It discovers the requested service and routes the ping request to it
+// With ?all=true it pings every instance of the service in parallel
 func (api *Api) pingService(c *fiber.Ctx) error {
 	serviceName := c.Params("serviceName")
 
@@ -22,8 +24,16 @@ func (api *Api) pingService(c *fiber.Ctx) error {
 		})
 	}
 
+	// ?all=true fans out to every healthy instance instead of one
+	if c.QueryBool("all") {
+		return api.pingServiceInstances(c, serviceName)
+	}
+
 	// Use service discovery to find and ping the service
-	response, err := api.service.PingService(&service.PingServiceParam{ServiceName: serviceName})
+	response, err := api.service.PingService(&service.PingServiceParam{
+		ServiceName: serviceName,
+		Headers:     middleware.AuthHeaders(c),
+	})
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "failed to ping service",
@@ -34,3 +44,24 @@ func (api *Api) pingService(c *fiber.Ctx) error {
 
 	return c.JSON(response)
 }
+
+// pingServiceInstances pings every healthy instance of a service in parallel
+func (api *Api) pingServiceInstances(c *fiber.Ctx, serviceName string) error {
+	responses, err := api.service.PingServiceInstances(&service.PingServiceInstancesParam{
+		ServiceName: serviceName,
+		Headers:     middleware.AuthHeaders(c),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "failed to ping service instances",
+			"service": serviceName,
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"service":   serviceName,
+		"instances": responses,
+		"count":     len(responses),
+	})
+}
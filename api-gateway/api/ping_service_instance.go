@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/middleware"
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// pingServiceInstance pings one specific instance of a service
+func (api *Api) pingServiceInstance(c *fiber.Ctx) error {
+	serviceName := c.Params("serviceName")
+	instanceID := c.Params("instanceID")
+
+	response, err := api.service.PingServiceInstance(&service.PingServiceInstanceParam{
+		ServiceName: serviceName,
+		InstanceID:  instanceID,
+		Headers:     middleware.AuthHeaders(c),
+	})
+	if err != nil {
+		status := 500
+		if errors.Is(err, service.ErrInstanceNotFound) {
+			status = 404
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error":    "failed to ping service instance",
+			"service":  serviceName,
+			"instance": instanceID,
+			"details":  err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
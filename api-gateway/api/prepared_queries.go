@@ -0,0 +1,24 @@
+package api
+
+import (
+	"errors"
+
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// preparedQueryUsage shows the minimal body of a prepared query, in Consul's format
+const preparedQueryUsage = `{"Name": "service-a-eu", "Service": {"Service": "service-a", "Tags": ["eu"], "Failover": {"NearestN": 2}}, "DNS": {"TTL": "10s"}}`
+
+// preparedQueryErrorStatus maps prepared query errors to HTTP status codes
+func preparedQueryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPreparedQuery):
+		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrPreparedQueryNotFound):
+		return fiber.StatusNotFound
+	}
+
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// revokeAPIKey revokes an API key on every gateway
+func (api *Api) revokeAPIKey(c *fiber.Ctx) error {
+	keyID := c.Params("keyID")
+
+	key, err := api.service.RevokeAPIKey(&service.RevokeAPIKeyParam{KeyID: keyID})
+	api.audit(c, "revoke_api_key", keyID, c.Query("reason"), err)
+	if err != nil {
+		return c.Status(apiKeyErrorStatus(err)).JSON(fiber.Map{
+			"error":   "failed to revoke API key",
+			"key_id":  keyID,
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"key":     key,
+		"message": "API key revoked",
+	})
+}
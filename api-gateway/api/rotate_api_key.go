@@ -0,0 +1,28 @@
+package api
+
+import (
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// rotateAPIKey issues a new secret for an API key; the previous key stops working
+func (api *Api) rotateAPIKey(c *fiber.Ctx) error {
+	keyID := c.Params("keyID")
+
+	response, err := api.service.RotateAPIKey(&service.RotateAPIKeyParam{KeyID: keyID})
+	api.audit(c, "rotate_api_key", keyID, c.Query("reason"), err)
+	if err != nil {
+		return c.Status(apiKeyErrorStatus(err)).JSON(fiber.Map{
+			"error":   "failed to rotate API key",
+			"key_id":  keyID,
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"key":     response.Key,
+		"token":   response.Token,
+		"message": "API key rotated, store the token now: it cannot be shown again",
+	})
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"api-gateway/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// updatePreparedQuery replaces a prepared query definition
+func (api *Api) updatePreparedQuery(c *fiber.Ctx) error {
+	queryID := c.Params("queryID")
+
+	var request service.PreparedQuery
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "invalid request body",
+			"details": err.Error(),
+			"usage":   "PUT /admin/prepared-queries/{query-id} " + preparedQueryUsage,
+		})
+	}
+
+	query, err := api.service.UpdatePreparedQuery(&service.UpdatePreparedQueryParam{
+		QueryID: queryID,
+		Query:   &request,
+	})
+	api.audit(c, "update_prepared_query", queryID, "name="+request.Name+" service="+request.Service.Service, err)
+	if err != nil {
+		return c.Status(preparedQueryErrorStatus(err)).JSON(fiber.Map{
+			"error":    "failed to update prepared query",
+			"query_id": queryID,
+			"details":  err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"query":   query,
+		"message": "Prepared query updated",
+	})
+}
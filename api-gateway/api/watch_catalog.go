@@ -0,0 +1,164 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"api-gateway/service"
+	"api-gateway/util/catalog"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// heartbeatInterval is how often an idle stream writes, to keep proxies from
+// closing it and to notice clients that went away
+const heartbeatInterval = 15 * time.Second
+
+// watchCatalog streams changes of services, instances and their health.
+// Events are sent as Server-Sent Events, or as newline-delimited JSON with
+// ?format=ndjson (or Accept: application/x-ndjson).
+// Optional query parameters: ?service=service-a,service-b and ?tag=api select events;
+// a Last-Event-ID header (or ?last_event_id=) resumes after the last event received.
+func (api *Api) watchCatalog(c *fiber.Ctx) error {
+	format := c.Query("format")
+	if format == "" {
+		format = "sse"
+		if strings.Contains(c.Get(fiber.HeaderAccept), "application/x-ndjson") {
+			format = "ndjson"
+		}
+	}
+	if format != "sse" && format != "ndjson" {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "invalid format",
+			"details": "format must be sse or ndjson",
+		})
+	}
+
+	param := &service.SubscribeCatalogEventsParam{}
+
+	// Ids from another gateway process (or malformed ones) start the stream with a reset
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	if lastEventID != "" {
+		param.LastEventID = &lastEventID
+	}
+
+	filter := catalog.Filter{
+		Services: splitList(c.Query("service")),
+		Tags:     splitList(c.Query("tag")),
+	}
+
+	events := api.service.SubscribeCatalogEvents(param)
+
+	if format == "sse" {
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+	} else {
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	}
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Disable buffering in nginx
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer events.Subscription.Close()
+
+		write := func(event catalog.Event) error {
+			if err := writeCatalogEvent(w, format, event); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		if format == "sse" {
+			// Ask browsers to reconnect after 3s; they send Last-Event-ID on their own
+			fmt.Fprintf(w, "retry: 3000\n\n")
+		}
+
+		if events.Reset {
+			if err := write(catalog.Event{Type: catalog.Reset, Time: time.Now()}); err != nil {
+				return
+			}
+		}
+
+		for _, event := range events.Missed {
+			if !filter.Match(event) {
+				continue
+			}
+			if err := write(event); err != nil {
+				return
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-events.Subscription.C:
+				// Closed on shutdown, or when this client fell behind; it reconnects and resumes
+				if !ok {
+					return
+				}
+
+				if !filter.Match(event) {
+					continue
+				}
+
+				if err := write(event); err != nil {
+					return
+				}
+
+			case <-heartbeat.C:
+				if format == "sse" {
+					fmt.Fprintf(w, ": heartbeat\n\n")
+				} else {
+					fmt.Fprintf(w, "{\"type\":\"heartbeat\",\"time\":%q}\n", time.Now().UTC().Format(time.RFC3339))
+				}
+
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeCatalogEvent writes one event as an SSE message or an NDJSON line
+func writeCatalogEvent(w *bufio.Writer, format string, event catalog.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if format == "ndjson" {
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	if event.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", event.ID)
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+
+	return err
+}
+
+// splitList splits a comma separated query parameter
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
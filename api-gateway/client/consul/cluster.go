@@ -0,0 +1,83 @@
+package consul
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ClusterInfo describes the Consul cluster the gateway talks to
+type ClusterInfo struct {
+	Datacenter string   `json:"datacenter"`
+	Agent      string   `json:"agent"`  // Node name of the agent the gateway queries
+	Leader     string   `json:"leader"` // Raft leader address, empty without a leader
+	Peers      []string `json:"peers"`  // Raft peer addresses (the servers)
+	Members    []Member `json:"members"`
+}
+
+// Member is a Consul agent in the gossip pool of the local agent
+type Member struct {
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	Port       uint16 `json:"port"`
+	Role       string `json:"role"`   // server or client
+	Status     string `json:"status"` // alive, leaving, left or failed
+	Datacenter string `json:"datacenter"`
+	Version    string `json:"version"`
+}
+
+// memberStatuses names the serf member statuses reported by the agent
+var memberStatuses = map[int]string{0: "none", 1: "alive", 2: "leaving", 3: "left", 4: "failed"}
+
+// ClusterInfo returns the datacenter, Raft leader and peers, and agent members of the cluster
+func (d *DiscoveryClient) ClusterInfo() (*ClusterInfo, error) {
+	self, err := d.client.Agent().Self()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consul agent: %w", err)
+	}
+
+	info := &ClusterInfo{}
+	if config, ok := self["Config"]; ok {
+		info.Datacenter, _ = config["Datacenter"].(string)
+		info.Agent, _ = config["NodeName"].(string)
+	}
+
+	// Consul answers with an empty address while the cluster has no leader
+	info.Leader, err = d.client.Status().Leader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raft leader: %w", err)
+	}
+
+	info.Peers, err = d.client.Status().Peers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raft peers: %w", err)
+	}
+	sort.Strings(info.Peers)
+
+	members, err := d.client.Agent().Members(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent members: %w", err)
+	}
+
+	for _, member := range members {
+		role := "client"
+		if member.Tags[api.MemberTagKeyRole] == api.MemberTagValueRoleServer {
+			role = "server"
+		}
+
+		info.Members = append(info.Members, Member{
+			Name:       member.Name,
+			Address:    member.Addr,
+			Port:       member.Port,
+			Role:       role,
+			Status:     memberStatuses[member.Status],
+			Datacenter: member.Tags[api.MemberTagKeyDatacenter],
+			Version:    strings.SplitN(member.Tags["build"], ":", 2)[0],
+		})
+	}
+	sort.Slice(info.Members, func(i, j int) bool { return info.Members[i].Name < info.Members[j].Name })
+
+	return info, nil
+}
@@ -0,0 +1,46 @@
+package consul
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// NodeRTTs estimates the network round-trip time from the local agent to every node
+// of its datacenter, from the Serf network coordinates Consul maintains
+func (d *DiscoveryClient) NodeRTTs() (map[string]time.Duration, error) {
+	node, err := d.LocalNode()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, _, err := d.client.Coordinate().Nodes(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network coordinates: %w", err)
+	}
+
+	var local *api.CoordinateEntry
+	for _, entry := range entries {
+		if entry.Node == node && entry.Coord != nil {
+			local = entry
+			break
+		}
+	}
+
+	if local == nil {
+		return nil, fmt.Errorf("failed to read network coordinates: no coordinate for local node %s yet", node)
+	}
+
+	// Coordinates are only comparable within a network segment
+	rtts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		if entry.Segment != local.Segment || entry.Coord == nil || !local.Coord.IsCompatibleWith(entry.Coord) {
+			continue
+		}
+
+		rtts[entry.Node] = local.Coord.DistanceTo(entry.Coord)
+	}
+
+	return rtts, nil
+}
@@ -1,11 +1,15 @@
 package consul
 
 import (
+	"errors"
 	"fmt"
-	"math/rand"
-	"time"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 
 	"api-gateway/util/config"
+	"api-gateway/util/settings"
 
 	"github.com/hashicorp/consul/api"
 )
@@ -13,27 +17,53 @@ import (
 // DiscoveryClient handles service discovery using Consul
 type DiscoveryClient struct {
 	client *api.Client
+
+	watches sync.Map // KV prefix -> *watchState
+
+	localDatacenter string // Read from the agent on first use
+	localNode       string // Read from the agent on first use
+	localAgentLock  sync.Mutex
 }
 
+// ErrUnknownDatacenter is returned when Consul has no route to the requested datacenter
+var ErrUnknownDatacenter = errors.New("unknown datacenter")
+
 // ServiceInstance represents a discovered service instance
 type ServiceInstance struct {
-	ID      string
-	Name    string
-	Address string
-	Port    int
-	Tags    []string
-	Meta    map[string]string
+	ID         string
+	Name       string
+	Address    string
+	Port       int
+	Tags       []string
+	Meta       map[string]string
+	Weights    Weights
+	Datacenter string
+	Node       string   // Consul node the instance runs on
+	Status     string   // Aggregated check status: passing, warning, critical or maintenance
+	Load       *float64 // Self-reported load from 0 (idle) to 1 (fully loaded), nil when not reported
+}
+
+// Weights are the relative amounts of traffic an instance should receive
+// while its checks are passing or warning
+type Weights struct {
+	Passing int `json:"passing"`
+	Warning int `json:"warning"`
+}
+
+// Scheme returns the URL scheme the instance is served on,
+// taken from the "protocol" meta key the services register with
+func (i ServiceInstance) Scheme() string {
+	if i.Meta["protocol"] == "https" {
+		return "https"
+	}
+
+	return "http"
 }
 
 // NewDiscoveryClient creates a new Consul discovery client
 func NewDiscoveryClient(config config.Consul) (*DiscoveryClient, error) {
-	// Create Consul client configuration
-	consulConfig := api.DefaultConfig()
-	consulConfig.Address = fmt.Sprintf("%s:%d", config.Host, config.Port)
-	consulConfig.Scheme = config.Scheme
-
 	// Create Consul client
-	client, err := api.NewClient(consulConfig)
+	client, err := api.NewClient(newConsulConfig(config))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create consul client: %w", err)
 	}
@@ -43,58 +73,162 @@ func NewDiscoveryClient(config config.Consul) (*DiscoveryClient, error) {
 	}, nil
 }
 
-// DiscoverService finds healthy instances of a service
+// newConsulConfig builds the Consul API client configuration,
+// including the ACL token and TLS settings
+func newConsulConfig(config config.Consul) *api.Config {
+	consulConfig := api.DefaultConfig()
+	consulConfig.Address = fmt.Sprintf("%s:%d", config.Host, config.Port)
+	consulConfig.Scheme = config.Scheme
+
+	// ACL token
+	consulConfig.Token = config.Token
+	consulConfig.TokenFile = config.TokenFile
+
+	// TLS
+	consulConfig.TLSConfig = api.TLSConfig{
+		Address:            config.TLSServerName,
+		CAFile:             config.CAFile,
+		CertFile:           config.CertFile,
+		KeyFile:            config.KeyFile,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	return consulConfig
+}
+
+// DiscoverService finds healthy instances of a service in the local datacenter
 // Returns all available instances for load balancing
 func (d *DiscoveryClient) DiscoverService(serviceName string) ([]ServiceInstance, error) {
-	// Query Consul for healthy instances of the service
-	services, _, err := d.client.Health().Service(serviceName, "", true, nil)
+	return d.DiscoverServiceIn(serviceName, "", settings.HealthPassing)
+}
+
+// DiscoverServiceIn finds the instances of a service that the health policy admits
+// in a datacenter, the local one when datacenter is empty
+func (d *DiscoveryClient) DiscoverServiceIn(serviceName, datacenter, health string) ([]ServiceInstance, error) {
+	// Query Consul for the instances of the service, only passing ones unless the policy admits more
+	passingOnly := health == "" || health == settings.HealthPassing
+	entries, _, err := d.client.Health().Service(serviceName, "", passingOnly, &api.QueryOptions{Datacenter: datacenter})
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover service %s: %w", serviceName, err)
+		return nil, fmt.Errorf("failed to discover service %s: %w", serviceName, datacenterError(err))
 	}
 
+	services := slices.DeleteFunc(entries, func(entry *api.ServiceEntry) bool {
+		return !admitted(health, entry.Checks.AggregatedStatus())
+	})
+
 	// Check if any instances are available
 	if len(services) == 0 {
+		if datacenter != "" {
+			return nil, fmt.Errorf("no healthy instances of service %s found in %s", serviceName, datacenter)
+		}
 		return nil, fmt.Errorf("no healthy instances of service %s found", serviceName)
 	}
 
 	// Convert Consul response to our ServiceInstance format
 	var instances []ServiceInstance
 	for _, service := range services {
-		instance := ServiceInstance{
-			ID:      service.Service.ID,
-			Name:    service.Service.Service,
-			Address: service.Service.Address,
-			Port:    service.Service.Port,
-			Tags:    service.Service.Tags,
-			Meta:    service.Service.Meta,
-		}
-		instances = append(instances, instance)
+		instances = append(instances, toServiceInstance(service))
 	}
 
 	return instances, nil
 }
 
-// DiscoverServiceWithLoadBalancing finds a service and returns one instance using round-robin
-func (d *DiscoveryClient) DiscoverServiceWithLoadBalancing(serviceName string) (*ServiceInstance, error) {
-	instances, err := d.DiscoverService(serviceName)
-	if err != nil {
-		return nil, err
+// admitted reports whether a health policy admits an instance with the given check status.
+// Instances in maintenance mode are drained and never admitted.
+func admitted(health, status string) bool {
+	switch health {
+	case settings.HealthWarning:
+		return status == api.HealthPassing || status == api.HealthWarning
+	case settings.HealthAny:
+		return status != api.HealthMaint
 	}
 
-	// Simple random load balancing
-	// In production, you might want more sophisticated algorithms
-	rand.Seed(time.Now().UnixNano())
-	selectedInstance := instances[rand.Intn(len(instances))]
-
-	return &selectedInstance, nil
+	return status == api.HealthPassing
 }
 
-// GetAllServices returns all available services in Consul
+// GetAllServices returns all available services in the local datacenter
 func (d *DiscoveryClient) GetAllServices() (map[string][]string, error) {
-	services, _, err := d.client.Catalog().Services(nil)
+	return d.GetAllServicesIn("")
+}
+
+// GetAllServicesIn returns all available services in a datacenter, the local one when empty
+func (d *DiscoveryClient) GetAllServicesIn(datacenter string) (map[string][]string, error) {
+	services, _, err := d.client.Catalog().Services(&api.QueryOptions{Datacenter: datacenter})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all services: %w", err)
+		return nil, fmt.Errorf("failed to get all services: %w", datacenterError(err))
 	}
 
 	return services, nil
 }
+
+// Datacenters returns every known datacenter, nearest first by estimated round-trip time
+func (d *DiscoveryClient) Datacenters() ([]string, error) {
+	datacenters, err := d.client.Catalog().Datacenters()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datacenters: %w", err)
+	}
+
+	return datacenters, nil
+}
+
+// LocalDatacenter returns the datacenter of the agent the gateway talks to
+func (d *DiscoveryClient) LocalDatacenter() (string, error) {
+	if err := d.readLocalAgent(); err != nil {
+		return "", fmt.Errorf("failed to read local datacenter: %w", err)
+	}
+
+	return d.localDatacenter, nil
+}
+
+// LocalNode returns the node name of the agent the gateway talks to
+func (d *DiscoveryClient) LocalNode() (string, error) {
+	if err := d.readLocalAgent(); err != nil {
+		return "", fmt.Errorf("failed to read local node: %w", err)
+	}
+
+	return d.localNode, nil
+}
+
+// readLocalAgent reads the datacenter and node name of the local agent once
+func (d *DiscoveryClient) readLocalAgent() error {
+	d.localAgentLock.Lock()
+	defer d.localAgentLock.Unlock()
+
+	if d.localDatacenter != "" {
+		return nil
+	}
+
+	self, err := d.client.Agent().Self()
+	if err != nil {
+		return err
+	}
+
+	datacenter, _ := self["Config"]["Datacenter"].(string)
+	node, _ := self["Config"]["NodeName"].(string)
+	if datacenter == "" || node == "" {
+		return fmt.Errorf("agent did not report its datacenter and node name")
+	}
+
+	d.localDatacenter, d.localNode = datacenter, node
+
+	return nil
+}
+
+// hasStatus reports whether err is an HTTP error response from Consul with the given status code
+func hasStatus(err error, code int) bool {
+	var statusErr api.StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == code
+}
+
+// datacenterError marks the error Consul returns for an unknown datacenter.
+// Consul has no dedicated status code for it: it answers 500 and names the cause in the body.
+// Transport errors never count, even when they mention a datacenter.
+func datacenterError(err error) error {
+	var statusErr api.StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusInternalServerError &&
+		strings.Contains(statusErr.Body, "No path to datacenter") {
+		return fmt.Errorf("%w: %v", ErrUnknownDatacenter, err)
+	}
+
+	return err
+}
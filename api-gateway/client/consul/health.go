@@ -0,0 +1,124 @@
+package consul
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// InstanceHealth represents a service instance together with its Consul health
+type InstanceHealth struct {
+	Instance    ServiceInstance `json:"instance"`
+	Node        string          `json:"node"`
+	NodeAddress string          `json:"node_address"`
+	Datacenter  string          `json:"datacenter"`
+	Status      string          `json:"status"` // passing, warning, critical or maintenance
+	Checks      []HealthCheck   `json:"checks"`
+}
+
+// HealthCheck represents a single Consul health check of an instance or of its node
+type HealthCheck struct {
+	CheckID     string `json:"check_id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`       // e.g. http, ttl; empty for the node's serf check
+	ServiceID   string `json:"service_id"` // Empty for node checks
+	Status      string `json:"status"`
+	Output      string `json:"output"`
+	Notes       string `json:"notes"`
+	ModifyIndex uint64 `json:"modify_index"` // Raft index of the last change
+}
+
+// GetServiceHealth returns every registered instance of a service, healthy or not
+func (d *DiscoveryClient) GetServiceHealth(serviceName string) ([]InstanceHealth, error) {
+	entries, _, err := d.client.Health().Service(serviceName, "", false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health of service %s: %w", serviceName, err)
+	}
+
+	var instances []InstanceHealth
+	for _, entry := range entries {
+		health := InstanceHealth{
+			Instance:    toServiceInstance(entry),
+			Node:        entry.Node.Node,
+			NodeAddress: entry.Node.Address,
+			Datacenter:  entry.Node.Datacenter,
+			Status:      entry.Checks.AggregatedStatus(),
+		}
+
+		for _, check := range entry.Checks {
+			health.Checks = append(health.Checks, toHealthCheck(check))
+		}
+
+		instances = append(instances, health)
+	}
+
+	return instances, nil
+}
+
+// toServiceInstance converts a Consul health entry to our ServiceInstance format
+func toServiceInstance(entry *api.ServiceEntry) ServiceInstance {
+	address := entry.Service.Address
+	if address == "" {
+		// Services registered without an address are reachable at the node address
+		address = entry.Node.Address
+	}
+
+	return ServiceInstance{
+		ID:      entry.Service.ID,
+		Name:    entry.Service.Service,
+		Address: address,
+		Port:    entry.Service.Port,
+		Tags:    entry.Service.Tags,
+		Meta:    entry.Service.Meta,
+		Weights: Weights{
+			Passing: entry.Service.Weights.Passing,
+			Warning: entry.Service.Weights.Warning,
+		},
+		Datacenter: entry.Node.Datacenter,
+		Node:       entry.Node.Node,
+		Status:     entry.Checks.AggregatedStatus(),
+		Load:       reportedLoad(entry.Checks),
+	}
+}
+
+// reportedLoad reads the load an instance reports in the output of its load check,
+// the check whose ID ends in ":load", e.g. "load=0.42 in_flight=3 cpu=0.20"
+func reportedLoad(checks api.HealthChecks) *float64 {
+	for _, check := range checks {
+		if check.ServiceID == "" || !strings.HasSuffix(check.CheckID, ":load") {
+			continue
+		}
+
+		for _, field := range strings.Fields(check.Output) {
+			value, ok := strings.CutPrefix(field, "load=")
+			if !ok {
+				continue
+			}
+
+			load, err := strconv.ParseFloat(value, 64)
+			if err != nil || load < 0 {
+				return nil
+			}
+			load = min(load, 1)
+
+			return &load
+		}
+	}
+
+	return nil
+}
+
+func toHealthCheck(check *api.HealthCheck) HealthCheck {
+	return HealthCheck{
+		CheckID:     check.CheckID,
+		Name:        check.Name,
+		Type:        check.Type,
+		ServiceID:   check.ServiceID,
+		Status:      check.Status,
+		Output:      check.Output,
+		Notes:       check.Notes,
+		ModifyIndex: check.ModifyIndex,
+	}
+}
@@ -0,0 +1,187 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// watchRetryDelay is how long a watch waits after a failed blocking query
+const watchRetryDelay = 5 * time.Second
+
+// watchWaitTime is how long a single blocking query may block in Consul
+const watchWaitTime = 5 * time.Minute
+
+// WatchState is the sync state of a KV prefix watch, used to judge how fresh the data is
+type WatchState struct {
+	Prefix       string     `json:"prefix"`
+	Synced       bool       `json:"synced"`              // The initial read succeeded
+	LastSync     time.Time  `json:"last_sync,omitempty"` // Last successful blocking query
+	FailingSince *time.Time `json:"failing_since,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// watchState records the sync state of one watch
+type watchState struct {
+	mu    sync.Mutex
+	state WatchState
+}
+
+func (w *watchState) succeeded() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.state.Synced = true
+	w.state.LastSync = time.Now()
+	w.state.FailingSince = nil
+	w.state.LastError = ""
+}
+
+func (w *watchState) failed(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.state.FailingSince == nil {
+		now := time.Now()
+		w.state.FailingSince = &now
+	}
+	w.state.LastError = err.Error()
+}
+
+// WatchStates returns the sync state of every running prefix watch
+func (d *DiscoveryClient) WatchStates() []WatchState {
+	var states []WatchState
+
+	d.watches.Range(func(_, value any) bool {
+		watch := value.(*watchState)
+
+		watch.mu.Lock()
+		states = append(states, watch.state)
+		watch.mu.Unlock()
+
+		return true
+	})
+
+	sort.Slice(states, func(i, j int) bool { return states[i].Prefix < states[j].Prefix })
+
+	return states
+}
+
+// WatchPrefix calls handler with every key under prefix whenever one of them changes.
+// It uses Consul blocking queries, so changes are delivered within milliseconds.
+// Keys passed to handler are relative to prefix. WatchPrefix returns when ctx is done.
+func (d *DiscoveryClient) WatchPrefix(ctx context.Context, prefix string, handler func(index uint64, entries map[string][]byte)) {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+
+	watch := &watchState{state: WatchState{Prefix: strings.TrimSuffix(prefix, "/")}}
+	d.watches.Store(prefix, watch)
+	defer d.watches.Delete(prefix)
+
+	var lastIndex uint64
+	for {
+		options := (&api.QueryOptions{WaitIndex: lastIndex, WaitTime: watchWaitTime}).WithContext(ctx)
+
+		index, entries, err := d.listPrefix(prefix, options)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			watch.failed(err)
+			log.Printf("❌ Failed to watch KV: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryDelay):
+			}
+
+			continue
+		}
+
+		watch.succeeded()
+
+		// Blocking query timed out without changes
+		if index == lastIndex {
+			continue
+		}
+
+		// The index went backwards (e.g. Consul snapshot restore): start over
+		if index < lastIndex {
+			lastIndex = 0
+			continue
+		}
+
+		lastIndex = index
+
+		handler(lastIndex, entries)
+	}
+}
+
+// ReadPrefix returns every key under prefix once, relative to prefix
+func (d *DiscoveryClient) ReadPrefix(prefix string) (uint64, map[string][]byte, error) {
+	return d.listPrefix(strings.TrimSuffix(prefix, "/")+"/", nil)
+}
+
+func (d *DiscoveryClient) listPrefix(prefix string, options *api.QueryOptions) (uint64, map[string][]byte, error) {
+	pairs, meta, err := d.client.KV().List(prefix, options)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list KV prefix %s: %w", prefix, err)
+	}
+
+	entries := make(map[string][]byte, len(pairs))
+	for _, pair := range pairs {
+		entries[strings.TrimPrefix(pair.Key, prefix)] = pair.Value
+	}
+
+	return meta.LastIndex, entries, nil
+}
+
+// GetKey returns the value and modify index of key, or a nil value when it does not exist
+func (d *DiscoveryClient) GetKey(key string) ([]byte, uint64, error) {
+	pair, _, err := d.client.KV().Get(key, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read KV key %s: %w", key, err)
+	}
+
+	if pair == nil {
+		return nil, 0, nil
+	}
+
+	return pair.Value, pair.ModifyIndex, nil
+}
+
+// PutKeyCAS writes key only if its modify index still equals index (0: the key must not exist).
+// It returns false when the key was changed concurrently.
+func (d *DiscoveryClient) PutKeyCAS(key string, value []byte, index uint64) (bool, error) {
+	ok, _, err := d.client.KV().CAS(&api.KVPair{Key: key, Value: value, ModifyIndex: index}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to write KV key %s: %w", key, err)
+	}
+
+	return ok, nil
+}
+
+// PutKey writes key
+func (d *DiscoveryClient) PutKey(key string, value []byte) error {
+	if _, err := d.client.KV().Put(&api.KVPair{Key: key, Value: value}, nil); err != nil {
+		return fmt.Errorf("failed to write KV key %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// DeleteKey deletes key
+func (d *DiscoveryClient) DeleteKey(key string) error {
+	if _, err := d.client.KV().Delete(key, nil); err != nil {
+		return fmt.Errorf("failed to delete KV key %s: %w", key, err)
+	}
+
+	return nil
+}
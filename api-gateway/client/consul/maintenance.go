@@ -0,0 +1,113 @@
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ErrInstanceNotFound is returned when no service instance has the requested id.
+// Maintenance mode only knows the instances registered with the local agent.
+var ErrInstanceNotFound = errors.New("instance not found")
+
+// CatalogInstance represents a service instance as stored in the Consul catalog,
+// regardless of its health
+type CatalogInstance struct {
+	ID      string
+	Name    string
+	Node    string
+	Address string
+	Port    int
+}
+
+// EnableServiceMaintenance puts a service instance into maintenance mode
+// Consul marks the instance critical so it is excluded from discovery results
+func (d *DiscoveryClient) EnableServiceMaintenance(instanceID, reason string) error {
+	err := d.client.Agent().EnableServiceMaintenance(instanceID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to enable maintenance for %s: %w", instanceID, agentServiceError(err))
+	}
+
+	return nil
+}
+
+// DisableServiceMaintenance takes a service instance out of maintenance mode
+func (d *DiscoveryClient) DisableServiceMaintenance(instanceID string) error {
+	err := d.client.Agent().DisableServiceMaintenance(instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to disable maintenance for %s: %w", instanceID, agentServiceError(err))
+	}
+
+	return nil
+}
+
+// agentServiceError marks the error the local agent returns for a service it does not know
+func agentServiceError(err error) error {
+	if hasStatus(err, http.StatusNotFound) {
+		return fmt.Errorf("%w: not registered with the local agent: %v", ErrInstanceNotFound, err)
+	}
+
+	return err
+}
+
+// FindInstance looks up a service instance by ID in the catalog.
+// Consul filters by ID, so it takes one query for the service name and one for the instance.
+func (d *DiscoveryClient) FindInstance(instanceID string) (*CatalogInstance, error) {
+	filter := &api.QueryOptions{Filter: "ServiceID == " + strconv.Quote(instanceID)}
+
+	services, _, err := d.client.Catalog().Services(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	for serviceName := range services {
+		entries, _, err := d.client.Catalog().Service(serviceName, "", filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances of %s: %w", serviceName, err)
+		}
+
+		for _, entry := range entries {
+			if entry.ServiceID == instanceID {
+				return &CatalogInstance{
+					ID:      entry.ServiceID,
+					Name:    entry.ServiceName,
+					Node:    entry.Node,
+					Address: entry.ServiceAddress,
+					Port:    entry.ServicePort,
+				}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: service instance %s not found in catalog", ErrInstanceNotFound, instanceID)
+}
+
+// DeregisterInstance forcefully removes a service instance from the catalog
+// This is meant for stale instances whose process is gone and never deregistered itself
+func (d *DiscoveryClient) DeregisterInstance(instanceID string) (*CatalogInstance, error) {
+	instance, err := d.FindInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the instance belongs to our local agent, remove it there first,
+	// otherwise anti-entropy would sync it back into the catalog
+	if _, _, err := d.client.Agent().Service(instanceID, nil); err == nil {
+		if err := d.client.Agent().ServiceDeregister(instanceID); err != nil {
+			return nil, fmt.Errorf("failed to deregister %s from local agent: %w", instanceID, err)
+		}
+	}
+
+	_, err = d.client.Catalog().Deregister(&api.CatalogDeregistration{
+		Node:      instance.Node,
+		ServiceID: instance.ID,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deregister %s from catalog: %w", instanceID, err)
+	}
+
+	return instance, nil
+}
@@ -0,0 +1,139 @@
+package consul
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// NodeInfo is a node of the catalog with its services and node-level health checks
+type NodeInfo struct {
+	Name       string            `json:"name"`
+	Address    string            `json:"address"`
+	Datacenter string            `json:"datacenter"`
+	Meta       map[string]string `json:"meta"`
+	Status     string            `json:"status"` // Aggregated status of the node checks
+	Services   []NodeService     `json:"services"`
+	Checks     []HealthCheck     `json:"checks"` // Node checks only, e.g. serfHealth
+}
+
+// NodeService is a service instance registered on a node
+type NodeService struct {
+	ID      string   `json:"id"`
+	Service string   `json:"service"`
+	Port    int      `json:"port"`
+	Tags    []string `json:"tags"`
+}
+
+// maxConcurrentQueries bounds the Consul requests Nodes makes in parallel
+const maxConcurrentQueries = 8
+
+// Nodes returns every node of the catalog with its services and node-level checks
+func (d *DiscoveryClient) Nodes() ([]NodeInfo, error) {
+	nodes, _, err := d.client.Catalog().Nodes(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	// One query for the checks of all nodes
+	checks, _, err := d.client.Health().State(api.HealthAny, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health checks: %w", err)
+	}
+
+	// One query per service instead of one per node; there are usually far fewer services
+	nodeServices, err := d.servicesByNode()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeChecks := make(map[string]api.HealthChecks)
+	for _, check := range checks {
+		if check.ServiceID == "" {
+			nodeChecks[check.Node] = append(nodeChecks[check.Node], check)
+		}
+	}
+
+	infos := make([]NodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		info := NodeInfo{
+			Name:       node.Node,
+			Address:    node.Address,
+			Datacenter: node.Datacenter,
+			Meta:       node.Meta,
+			Status:     nodeChecks[node.Node].AggregatedStatus(),
+			Services:   append([]NodeService{}, nodeServices[node.Node]...),
+			Checks:     []HealthCheck{},
+		}
+
+		for _, check := range nodeChecks[node.Node] {
+			info.Checks = append(info.Checks, toHealthCheck(check))
+		}
+
+		sort.Slice(info.Services, func(i, j int) bool { return info.Services[i].ID < info.Services[j].ID })
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	return infos, nil
+}
+
+// servicesByNode returns the service instances of every node, reading the
+// instances of up to maxConcurrentQueries services at a time
+func (d *DiscoveryClient) servicesByNode() (map[string][]NodeService, error) {
+	services, _, err := d.client.Catalog().Services(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	nodeServices := make(map[string][]NodeService)
+	limit := make(chan struct{}, maxConcurrentQueries)
+
+	for serviceName := range services {
+		wg.Add(1)
+
+		go func(serviceName string) {
+			defer wg.Done()
+
+			limit <- struct{}{}
+			entries, _, err := d.client.Catalog().Service(serviceName, "", nil)
+			<-limit
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to read instances of service %s: %w", serviceName, err)
+				}
+				return
+			}
+
+			for _, entry := range entries {
+				nodeServices[entry.Node] = append(nodeServices[entry.Node], NodeService{
+					ID:      entry.ServiceID,
+					Service: entry.ServiceName,
+					Port:    entry.ServicePort,
+					Tags:    entry.ServiceTags,
+				})
+			}
+		}(serviceName)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return nodeServices, nil
+}
@@ -0,0 +1,133 @@
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ErrPreparedQueryNotFound is returned when no prepared query has the requested id or name
+var ErrPreparedQueryNotFound = errors.New("prepared query not found")
+
+// PreparedQuery is a Consul prepared query definition, in Consul's own JSON format
+type PreparedQuery = api.PreparedQueryDefinition
+
+// QueryResult holds the instances a prepared query resolved to
+type QueryResult struct {
+	Service    string            // Service the query selected
+	Datacenter string            // Datacenter the instances came from, after any failover
+	Failovers  int               // Number of remote datacenters queried
+	TTL        time.Duration     // How long the result may be cached (the query's DNS TTL)
+	Instances  []ServiceInstance // Healthy instances, filtered as the query defines
+}
+
+// ListPreparedQueries returns every prepared query definition
+func (d *DiscoveryClient) ListPreparedQueries() ([]*PreparedQuery, error) {
+	queries, _, err := d.client.PreparedQuery().List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prepared queries: %w", err)
+	}
+
+	return queries, nil
+}
+
+// GetPreparedQuery returns a prepared query definition by id
+func (d *DiscoveryClient) GetPreparedQuery(queryID string) (*PreparedQuery, error) {
+	queries, _, err := d.client.PreparedQuery().Get(queryID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prepared query %s: %w", queryID, queryError(err))
+	}
+
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPreparedQueryNotFound, queryID)
+	}
+
+	return queries[0], nil
+}
+
+// CreatePreparedQuery creates a prepared query and returns its id
+func (d *DiscoveryClient) CreatePreparedQuery(query *PreparedQuery) (string, error) {
+	id, _, err := d.client.PreparedQuery().Create(query, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create prepared query: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdatePreparedQuery replaces the definition of the prepared query with query.ID
+func (d *DiscoveryClient) UpdatePreparedQuery(query *PreparedQuery) error {
+	// Consul answers changes to unknown queries with a generic error, so look the query up first
+	if _, err := d.GetPreparedQuery(query.ID); err != nil {
+		return err
+	}
+
+	_, err := d.client.PreparedQuery().Update(query, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update prepared query %s: %w", query.ID, err)
+	}
+
+	return nil
+}
+
+// DeletePreparedQuery deletes a prepared query by id
+func (d *DiscoveryClient) DeletePreparedQuery(queryID string) error {
+	if _, err := d.GetPreparedQuery(queryID); err != nil {
+		return err
+	}
+
+	_, err := d.client.PreparedQuery().Delete(queryID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete prepared query %s: %w", queryID, err)
+	}
+
+	return nil
+}
+
+// ExecutePreparedQuery resolves a prepared query by id or name. Consul applies the
+// query's tag filters and failover policy; datacenter is where the query is executed,
+// the local one when empty.
+func (d *DiscoveryClient) ExecutePreparedQuery(queryIDOrName, datacenter string) (*QueryResult, error) {
+	response, _, err := d.client.PreparedQuery().Execute(queryIDOrName, &api.QueryOptions{Datacenter: datacenter})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute prepared query %s: %w", queryIDOrName, datacenterError(queryError(err)))
+	}
+
+	result := &QueryResult{
+		Service:    response.Service,
+		Datacenter: response.Datacenter,
+		Failovers:  response.Failovers,
+	}
+
+	if response.DNS.TTL != "" {
+		if ttl, err := time.ParseDuration(response.DNS.TTL); err == nil {
+			result.TTL = ttl
+		}
+	}
+
+	for i := range response.Nodes {
+		instance := toServiceInstance(&response.Nodes[i])
+		if instance.Datacenter == "" {
+			instance.Datacenter = response.Datacenter
+		}
+		result.Instances = append(result.Instances, instance)
+	}
+
+	if len(result.Instances) == 0 {
+		return nil, fmt.Errorf("prepared query %s found no healthy instances of %s", queryIDOrName, response.Service)
+	}
+
+	return result, nil
+}
+
+// queryError marks the error Consul returns when reading or executing an unknown prepared query
+func queryError(err error) error {
+	if hasStatus(err, http.StatusNotFound) {
+		return fmt.Errorf("%w: %v", ErrPreparedQueryNotFound, err)
+	}
+
+	return err
+}
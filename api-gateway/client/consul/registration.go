@@ -0,0 +1,79 @@
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ErrCheckNotFound is returned when the agent does not know a check, e.g. because it
+// deregistered the service after the check stayed critical for too long
+var ErrCheckNotFound = errors.New("check not found")
+
+// Registration describes a gateway replica registered in Consul
+type Registration struct {
+	ID      string
+	Name    string
+	Address string
+	Port    int
+	Tags    []string
+	Meta    map[string]string
+
+	// The gateway reports its own health through a TTL check,
+	// which turns critical when no update arrives within CheckTTL.
+	// Consul removes the registration once the check stays critical for a minute.
+	CheckTTL time.Duration
+}
+
+// CheckID returns the id of the TTL check of the registration
+func (r Registration) CheckID() string {
+	return "service:" + r.ID
+}
+
+// Register registers a gateway replica with the local Consul agent
+func (d *DiscoveryClient) Register(registration Registration) error {
+	err := d.client.Agent().ServiceRegister(&api.AgentServiceRegistration{
+		ID:      registration.ID,
+		Name:    registration.Name,
+		Address: registration.Address,
+		Port:    registration.Port,
+		Tags:    registration.Tags,
+		Meta:    registration.Meta,
+		Check: &api.AgentServiceCheck{
+			CheckID:                        registration.CheckID(),
+			Name:                           "Gateway health",
+			TTL:                            registration.CheckTTL.String(),
+			Status:                         api.HealthPassing, // Count the replica right away
+			DeregisterCriticalServiceAfter: "1m",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register %s with consul: %w", registration.ID, err)
+	}
+
+	return nil
+}
+
+// Deregister removes a gateway replica from the local Consul agent
+func (d *DiscoveryClient) Deregister(id string) error {
+	if err := d.client.Agent().ServiceDeregister(id); err != nil {
+		return fmt.Errorf("failed to deregister %s from consul: %w", id, err)
+	}
+
+	return nil
+}
+
+// UpdateTTL reports the status (passing, warning or critical) of a TTL check
+func (d *DiscoveryClient) UpdateTTL(checkID, status, output string) error {
+	if err := d.client.Agent().UpdateTTL(checkID, output, status); err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			err = fmt.Errorf("%w: %v", ErrCheckNotFound, err)
+		}
+		return fmt.Errorf("failed to update check %s: %w", checkID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package consul
+
+import (
+	"fmt"
+)
+
+// Leader returns the address of the Consul leader, checking that Consul is reachable and has a leader
+func (d *DiscoveryClient) Leader() (string, error) {
+	leader, err := d.client.Status().Leader()
+	if err != nil {
+		return "", fmt.Errorf("failed to reach consul: %w", err)
+	}
+
+	if leader == "" {
+		return "", fmt.Errorf("consul has no leader")
+	}
+
+	return leader, nil
+}
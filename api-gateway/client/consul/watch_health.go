@@ -0,0 +1,132 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// WatchHealth calls handler with every registered instance and its health whenever
+// a health check changes state or an instance registers, deregisters or is updated.
+// It uses blocking queries on the health state (check changes) and on the catalog
+// (registrations, tags and meta), then reads the health of every service.
+// The first call delivers the current state. WatchHealth returns when ctx is done.
+func (d *DiscoveryClient) WatchHealth(ctx context.Context, handler func(instances []InstanceHealth)) {
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default: // A refresh is already pending
+		}
+	}
+
+	go d.watchIndex(ctx, "health state", func(options *api.QueryOptions) (uint64, error) {
+		_, meta, err := d.client.Health().State(api.HealthAny, options)
+		if err != nil {
+			return 0, err
+		}
+		return meta.LastIndex, nil
+	}, notify)
+
+	go d.watchIndex(ctx, "catalog", func(options *api.QueryOptions) (uint64, error) {
+		_, meta, err := d.client.Catalog().Services(options)
+		if err != nil {
+			return 0, err
+		}
+		return meta.LastIndex, nil
+	}, notify)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+
+		instances, err := d.allServiceHealth()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			log.Printf("❌ Failed to read service health: %v", err)
+
+			// Try again later, even if nothing else changes
+			time.AfterFunc(watchRetryDelay, notify)
+			continue
+		}
+
+		handler(instances)
+	}
+}
+
+// watchIndex runs a blocking query in a loop and calls changed every time its index moves
+func (d *DiscoveryClient) watchIndex(ctx context.Context, name string, query func(options *api.QueryOptions) (uint64, error), changed func()) {
+	var lastIndex uint64
+	for {
+		options := (&api.QueryOptions{WaitIndex: lastIndex, WaitTime: watchWaitTime}).WithContext(ctx)
+
+		index, err := query(options)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			log.Printf("❌ Failed to watch %s: %v", name, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryDelay):
+			}
+
+			continue
+		}
+
+		// Blocking query timed out without changes
+		if index == lastIndex {
+			continue
+		}
+
+		// The index went backwards (e.g. Consul snapshot restore): start over
+		if index < lastIndex {
+			lastIndex = 0
+			continue
+		}
+
+		lastIndex = index
+
+		changed()
+	}
+}
+
+// allServiceHealth returns every registered instance of every service, ordered by service and id
+func (d *DiscoveryClient) allServiceHealth() ([]InstanceHealth, error) {
+	services, err := d.GetAllServices()
+	if err != nil {
+		return nil, err
+	}
+
+	var all []InstanceHealth
+	for serviceName := range services {
+		instances, err := d.GetServiceHealth(serviceName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read health of %s: %w", serviceName, err)
+		}
+
+		all = append(all, instances...)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Instance.Name != all[j].Instance.Name {
+			return all[i].Instance.Name < all[j].Instance.Name
+		}
+		return all[i].Instance.ID < all[j].Instance.ID
+	})
+
+	return all, nil
+}
@@ -1,10 +1,13 @@
 package http_adapter
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"time"
 )
 
@@ -17,19 +20,44 @@ type Response struct {
 	StatusCode int                    `json:"status_code"`
 	Body       map[string]interface{} `json:"body"`
 	Headers    map[string]string      `json:"headers"`
+	Timing     Timing                 `json:"-"`
 }
 
-func NewClient() *Client {
+// DialTLSFunc opens a verified TLS connection to addr
+type DialTLSFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// NewClient creates an HTTP client for calling services
+// dialTLS opens the connections for https:// URLs, nil means Go's defaults
+func NewClient(dialTLS DialTLSFunc) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if dialTLS != nil {
+		transport.DialTLSContext = dialTLS
+	}
+
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
 	}
 }
 
-// Get makes a GET request to the specified URL
-func (c *Client) Get(url string) (*Response, error) {
-	resp, err := c.httpClient.Get(url)
+// Get makes a GET request to the specified URL with optional extra headers
+// The request is aborted when ctx is done (e.g. the route timeout expires)
+func (c *Client) Get(ctx context.Context, url string, headers map[string]string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GET request to %s: %w", url, err)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	trace := newTimingTrace()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make GET request to %s: %w", url, err)
 	}
@@ -40,6 +68,7 @@ func (c *Client) Get(url string) (*Response, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	timing := trace.done()
 
 	// Parse JSON response
 	var bodyMap map[string]interface{}
@@ -53,16 +82,17 @@ func (c *Client) Get(url string) (*Response, error) {
 	}
 
 	// Extract headers
-	headers := make(map[string]string)
+	responseHeaders := make(map[string]string)
 	for key, values := range resp.Header {
 		if len(values) > 0 {
-			headers[key] = values[0]
+			responseHeaders[key] = values[0]
 		}
 	}
 
 	return &Response{
 		StatusCode: resp.StatusCode,
 		Body:       bodyMap,
-		Headers:    headers,
+		Headers:    responseHeaders,
+		Timing:     timing,
 	}, nil
 }
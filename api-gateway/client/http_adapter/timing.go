@@ -0,0 +1,88 @@
+package http_adapter
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing breaks down where the time of a request went
+// DNS, Connect and TLS are zero when a kept-alive connection was reused
+type Timing struct {
+	DNS        time.Duration // Resolving the host name
+	Connect    time.Duration // Establishing the TCP connection
+	TLS        time.Duration // TLS handshake (https only)
+	FirstByte  time.Duration // From sending the request to the first response byte
+	Total      time.Duration // Round trip, including reading the body
+	ConnReused bool
+}
+
+// timingTrace records the phases of one request.
+// The callbacks may run concurrently: with Happy Eyeballs the IPv4 and IPv6
+// addresses are dialed in parallel, and only the first connection established counts.
+type timingTrace struct {
+	mu                 sync.Mutex
+	start              time.Time
+	dnsStart, tlsStart time.Time
+	connStarts         map[string]time.Time // network/address -> dial start
+	wroteRequest       time.Time
+	timing             Timing
+}
+
+func newTimingTrace() *timingTrace {
+	return &timingTrace{start: time.Now(), connStarts: make(map[string]time.Time)}
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(func() { t.dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.record(func() { t.timing.DNS = time.Since(t.dnsStart) }) },
+
+		ConnectStart: func(network, addr string) {
+			t.record(func() { t.connStarts[network+"/"+addr] = time.Now() })
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.record(func() {
+				if err == nil && t.timing.Connect == 0 {
+					t.timing.Connect = time.Since(t.connStarts[network+"/"+addr])
+				}
+			})
+		},
+
+		TLSHandshakeStart: func() { t.record(func() { t.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func() { t.timing.TLS = time.Since(t.tlsStart) })
+		},
+
+		GotConn: func(info httptrace.GotConnInfo) { t.record(func() { t.timing.ConnReused = info.Reused }) },
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.record(func() { t.wroteRequest = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			t.record(func() {
+				if !t.wroteRequest.IsZero() {
+					t.timing.FirstByte = time.Since(t.wroteRequest)
+				}
+			})
+		},
+	}
+}
+
+// record runs a trace callback under the lock
+func (t *timingTrace) record(callback func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	callback()
+}
+
+// done completes the timing once the response body was read
+func (t *timingTrace) done() Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timing.Total = time.Since(t.start)
+
+	return t.timing
+}
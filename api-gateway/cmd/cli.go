@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"api-gateway/client/consul"
+	"api-gateway/service"
+	"api-gateway/util/config"
+	"api-gateway/util/settings"
+	"api-gateway/util/table"
+)
+
+// Output formats supported by the operator subcommands
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// cliOptions holds the flags shared by all operator subcommands
+type cliOptions struct {
+	output  string
+	verbose bool
+	config  *config.Flags
+}
+
+// newCliFlagSet creates a flag set with the flags shared by all operator subcommands
+func newCliFlagSet(name, usage string) (*flag.FlagSet, *cliOptions) {
+	options := &cliOptions{}
+
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	options.config = config.RegisterFlags(fs)
+	fs.StringVar(&options.output, "o", outputTable, "output format: table or json")
+	fs.StringVar(&options.output, "output", outputTable, "output format: table or json")
+	fs.BoolVar(&options.verbose, "v", false, "show gateway logs")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+
+	return fs, options
+}
+
+// parseCliArgs parses flags and positional arguments in any order,
+// e.g. "ping service-a --all-instances" as well as "ping --all-instances service-a"
+func parseCliArgs(fs *flag.FlagSet, options *cliOptions, args []string) []string {
+	var positional []string
+
+	for {
+		// ExitOnError: Parse never returns an error
+		_ = fs.Parse(args)
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if options.output != outputTable && options.output != outputJSON {
+		fmt.Fprintf(os.Stderr, "unknown output format %q\n\n", options.output)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if !options.verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	return positional
+}
+
+// newCliService builds the service layer the same way the server does
+func newCliService(options *cliOptions) (*service.Service, config.Config) {
+	config, err := config.LoadConfig(options.config)
+	if err != nil {
+		fatalf("failed to load config: %v", err)
+	}
+
+	discoveryClient, err := consul.NewDiscoveryClient(config.Consul)
+	if err != nil {
+		fatalf("failed to initialize Consul discovery client: %v", err)
+	}
+
+	httpClient, err := newHttpClient(config.Upstream)
+	if err != nil {
+		fatalf("failed to initialize HTTP client: %v", err)
+	}
+
+	// Operator commands read the runtime settings once instead of watching them
+	settingsStore := settings.NewStore()
+	if config.DynamicConfig.Enabled {
+		index, entries, err := discoveryClient.ReadPrefix(config.DynamicConfig.KVPrefix)
+		if err == nil {
+			err = settingsStore.Apply(index, entries)
+		}
+		if err != nil {
+			fatalf("failed to load gateway settings: %v", err)
+		}
+	}
+
+	// API keys are only needed to authenticate gateway requests
+	return service.NewService(httpClient, discoveryClient, settingsStore, nil, nil, nil), config
+}
+
+// printJSON writes a value as indented JSON to stdout
+func printJSON(value interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(value); err != nil {
+		fatalf("failed to encode output: %v", err)
+	}
+}
+
+// printTable writes rows as an aligned text table to stdout
+func printTable(header []string, rows [][]string) {
+	table.Write(os.Stdout, header, rows)
+}
+
+// fatalf prints an error to stderr and exits with a non-zero code
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"os/user"
+
+	"api-gateway/service"
+	"api-gateway/util/audit"
+)
+
+// deregister force-removes a stale service instance from the Consul catalog
+func deregister() {
+	fs, options := newCliFlagSet("deregister", "deregister <instance-id> [--reason <text>] [-o table|json]")
+
+	reason := fs.String("reason", "", "reason recorded in the audit log")
+
+	args := parseCliArgs(fs, options, subcommandArgs())
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	svc, config := newCliService(options)
+
+	auditLogger, err := audit.NewLogger(config.Admin.AuditLogPath)
+	if err != nil {
+		fatalf("failed to initialize audit logger: %v", err)
+	}
+	defer auditLogger.Close()
+
+	instance, err := svc.DeregisterInstance(&service.DeregisterInstanceParam{InstanceID: args[0]})
+
+	// CLI actions are audited just like the admin API
+	entry := audit.Entry{
+		Actor:   "cli:" + currentUser(),
+		Action:  "deregister_instance",
+		Target:  args[0],
+		Reason:  *reason,
+		Success: err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	auditLogger.Record(entry)
+
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	if options.output == outputJSON {
+		printJSON(instance)
+		return
+	}
+
+	printTable([]string{"DEREGISTERED", "SERVICE", "NODE"}, [][]string{{instance.ID, instance.Name, instance.Node}})
+}
+
+// currentUser returns the OS user running the CLI
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return "unknown"
+}
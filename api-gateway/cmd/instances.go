@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"api-gateway/service"
+)
+
+// instances shows all instances of a service and their health
+func instances() {
+	fs, options := newCliFlagSet("instances", "instances <service-name> [-o table|json]")
+
+	args := parseCliArgs(fs, options, subcommandArgs())
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	svc, _ := newCliService(options)
+
+	instances, err := svc.GetServiceInstances(&service.GetServiceInstancesParam{ServiceName: args[0]})
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	if options.output == outputJSON {
+		printJSON(instances)
+		return
+	}
+
+	rows := make([][]string, 0, len(instances))
+	for _, health := range instances {
+		rows = append(rows, []string{
+			health.Instance.ID,
+			fmt.Sprintf("%s:%d", health.Instance.Address, health.Instance.Port),
+			health.Node,
+			health.Status,
+			strings.Join(health.Instance.Tags, ","),
+		})
+	}
+
+	printTable([]string{"ID", "ADDRESS", "NODE", "STATUS", "TAGS"}, rows)
+}
@@ -12,8 +12,13 @@ func main() {
 	flag.Parse()
 
 	cmds := map[string]func(){
-		"help":  help,
-		"start": start,
+		"help":       help,
+		"start":      start,
+		"services":   services,
+		"instances":  instances,
+		"ping":       ping,
+		"deregister": deregister,
+		"config":     configCommand,
 	}
 
 	if cmdFunc, ok := cmds[flag.Arg(0)]; ok {
@@ -34,8 +39,23 @@ func help() {
 			fmt.Sprintf(header, "Usage", "Description") +
 			fmt.Sprintf(divider, strings.Repeat("-", 30), strings.Repeat("-", 50)) +
 			fmt.Sprintf(row, "help", "show this help message") +
-			fmt.Sprintf(row, "start", "start the server") +
+			fmt.Sprintf(row, "start [flags]", "start the server (see start -h for flags)") +
+			fmt.Sprintf(row, "config validate [path]", "validate and print the effective configuration") +
+			fmt.Sprintf(row, "services [--tag <tag>]", "list registered services, optionally by tag") +
+			fmt.Sprintf(row, "instances <name>", "show instances of a service and their health") +
+			fmt.Sprintf(row, "ping <name> [--all-instances]", "ping a service through discovery") +
+			fmt.Sprintf(row, "deregister <id>", "force-remove a stale instance from Consul") +
+			fmt.Sprintf(row, "(all operator commands)", "accept -o table|json and -v for logs") +
 			fmt.Sprintf(divider, strings.Repeat("_", 30), strings.Repeat("_", 50))
 
 	fmt.Fprintln(os.Stderr, output)
 }
+
+// subcommandArgs returns the command line arguments following the subcommand name
+func subcommandArgs() []string {
+	if flag.NArg() < 2 {
+		return nil
+	}
+
+	return flag.Args()[1:]
+}
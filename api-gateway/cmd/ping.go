@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"api-gateway/service"
+)
+
+// ping pings a service through discovery, either one instance or all of them
+func ping() {
+	fs, options := newCliFlagSet("ping", "ping <service-name> [--all-instances] [-o table|json]")
+
+	allInstances := fs.Bool("all-instances", false, "ping every healthy instance instead of one")
+
+	args := parseCliArgs(fs, options, subcommandArgs())
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	svc, _ := newCliService(options)
+
+	var results []*service.PingServiceResponse
+	if *allInstances {
+		responses, err := svc.PingServiceInstances(&service.PingServiceInstancesParam{ServiceName: args[0]})
+		if err != nil {
+			fatalf("%v", err)
+		}
+
+		results = responses
+	} else {
+		response, err := svc.PingService(&service.PingServiceParam{ServiceName: args[0]})
+		if err != nil {
+			fatalf("%v", err)
+		}
+
+		results = []*service.PingServiceResponse{response}
+	}
+
+	if options.output == outputJSON {
+		if *allInstances {
+			printJSON(results)
+		} else {
+			printJSON(results[0])
+		}
+		return
+	}
+
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, []string{
+			result.Instance.ID,
+			fmt.Sprintf("%s:%d", result.Instance.Address, result.Instance.Port),
+			fmt.Sprintf("%d", result.StatusCode),
+			fmt.Sprintf("%.1fms", result.LatencyMs),
+			result.Message,
+		})
+	}
+
+	printTable([]string{"INSTANCE", "ADDRESS", "STATUS", "LATENCY", "MESSAGE"}, rows)
+}
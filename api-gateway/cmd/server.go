@@ -11,7 +11,8 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func runRestServer(port int, api *api.Api) {
+// runRestServer starts serving in the background and returns the app, so it can be shut down
+func runRestServer(port int, api *api.Api) *fiber.App {
 	// Init fiber app
 	app := fiber.New()
 
@@ -27,12 +28,16 @@ func runRestServer(port int, api *api.Api) {
 	app = api.DefineEndpoints(app)
 
 	// start the server
-	err := app.Listen(fmt.Sprintf(":%d", port))
-	if err != nil {
-		log.Printf("failed to listen at port: %v!", port)
+	go func() {
+		err := app.Listen(fmt.Sprintf(":%d", port))
+		if err != nil {
+			log.Printf("failed to listen at port: %v!", port)
 
-		os.Exit(1)
-	}
+			os.Exit(1)
+		}
+	}()
 
 	log.Printf("rest server started successfully 🚀")
+
+	return app
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"api-gateway/service"
+)
+
+// services lists the services registered in Consul, optionally filtered by tag
+func services() {
+	fs, options := newCliFlagSet("services", "services [--tag <tag>]... [-o table|json]")
+
+	var tags stringList
+	fs.Var(&tags, "tag", "only list services carrying this tag (repeatable)")
+
+	parseCliArgs(fs, options, subcommandArgs())
+
+	svc, _ := newCliService(options)
+
+	services, err := svc.GetServicesByTags(&service.GetServicesByTagsParam{Tags: tags})
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	if options.output == outputJSON {
+		printJSON(services)
+		return
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name, strings.Join(services[name], ",")})
+	}
+
+	printTable([]string{"SERVICE", "TAGS"}, rows)
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
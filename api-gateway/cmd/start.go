@@ -1,20 +1,46 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
 	"api-gateway/api"
 	"api-gateway/client/consul"
 	"api-gateway/client/http_adapter"
 	"api-gateway/service"
+	"api-gateway/util/apikey"
+	"api-gateway/util/audit"
+	"api-gateway/util/catalog"
 	"api-gateway/util/config"
+	"api-gateway/util/jwt"
+	"api-gateway/util/ratelimit"
+	"api-gateway/util/settings"
+	"api-gateway/util/tlsutil"
+	"api-gateway/util/uptime"
+	"api-gateway/util/webhook"
 )
 
+// gatewayCheckTTL is how long the gateway's health check stays valid without an update
+const gatewayCheckTTL = 30 * time.Second
+
+// shutdownTimeout is how long in-flight requests may take to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
 func start() {
-	// Load environment variables from .env file
-	config, err := config.LoadConfig(".")
+	// Parse the command line overrides of the configuration
+	fs := flag.NewFlagSet("start", flag.ExitOnError)
+	configFlags := config.RegisterFlags(fs)
+	fs.Parse(subcommandArgs())
+
+	// Load configuration from flags, environment variables and config file
+	config, err := config.LoadConfig(configFlags)
 	if err != nil {
 		log.Printf("failed to load config: %v", err)
 		os.Exit(1)
@@ -22,6 +48,10 @@ func start() {
 
 	log.Printf("Starting %s service ...", config.App.Name)
 
+	// Background watchers stop when the program ends
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Init Consul discovery client
 	log.Printf("Initializing Consul discovery client...")
 	discoveryClient, err := consul.NewDiscoveryClient(config.Consul)
@@ -31,24 +61,252 @@ func start() {
 	}
 	log.Printf("✅ Consul discovery client initialized successfully")
 
-	// Init HTTP client
-	httpClient := http_adapter.NewClient()
+	// Init HTTP client (with mTLS certificates for https services)
+	httpClient, err := newHttpClient(config.Upstream)
+	if err != nil {
+		log.Printf("failed to initialize HTTP client: %v", err)
+		os.Exit(1)
+	}
+
+	// Init runtime settings, optionally watched from Consul KV
+	settingsStore := settings.NewStore()
+	if config.DynamicConfig.Enabled {
+		log.Printf("Watching gateway settings in Consul KV prefix %s ...", config.DynamicConfig.KVPrefix)
+
+		go discoveryClient.WatchPrefix(ctx, config.DynamicConfig.KVPrefix, func(index uint64, entries map[string][]byte) {
+			if err := settingsStore.Apply(index, entries); err != nil {
+				log.Printf("❌ Rejected gateway settings revision %d, keeping revision %d: %v",
+					index, settingsStore.Current().Revision, err)
+			}
+		})
+	}
+
+	// Init API keys, watched from Consul KV so revocations apply within seconds
+	var apiKeys *apikey.Store
+	if config.APIKeys.Enabled {
+		log.Printf("Watching API keys in Consul KV prefix %s ...", config.APIKeys.KVPrefix)
+
+		apiKeys = apikey.NewStore(config.APIKeys.KVPrefix)
+		go discoveryClient.WatchPrefix(ctx, config.APIKeys.KVPrefix, apiKeys.Apply)
+	}
+
+	// Register the gateway in Consul; it reports its own health through a TTL check.
+	// Without Consul the gateway starts anyway, the health reports register it once Consul is reachable.
+	registration, err := gatewayRegistration(config.App)
+	if err != nil {
+		log.Printf("failed to register gateway: %v", err)
+		os.Exit(1)
+	}
+	if err := discoveryClient.Register(registration); err != nil {
+		log.Printf("⚠️ %v, registering again with the next health report", err)
+	} else {
+		log.Printf("✅ Gateway registered with Consul as %s", registration.ID)
+	}
+	healthReport := &service.ReportHealthParam{Registration: registration}
+
+	// Init rate limiter, shared with the other gateway replicas in cluster mode
+	rateLimiter := ratelimit.NewLimiter()
+	var rateLimitSync *service.SyncRateLimitsParam
+	if config.Cluster.Enabled {
+		rateLimitSync = &service.SyncRateLimitsParam{
+			Cluster:     ratelimit.NewCluster(rateLimiter, registration.ID),
+			ServiceName: config.App.Name,
+			KVPrefix:    config.Cluster.KVPrefix,
+		}
+	}
+
+	// Init uptime store for the background prober
+	var uptimeStore *uptime.Store
+	var prober *service.RunProberParam
+	if config.Prober.Enabled {
+		uptimeStore, err = uptime.Open(config.Prober.StorePath, config.Prober.Retention)
+		if err != nil {
+			log.Printf("failed to open uptime store: %v", err)
+			os.Exit(1)
+		}
+		defer uptimeStore.Close()
+
+		prober = &service.RunProberParam{Interval: config.Prober.Interval}
+		log.Printf("🛰️ Probing services every %s, keeping %s of history in %s",
+			config.Prober.Interval, config.Prober.Retention, config.Prober.StorePath)
+	}
+
+	// Init the catalog hub, which streams catalog and health changes to watchers and webhooks
+	catalogHub := catalog.NewHub()
+
+	// Init webhooks, notified about catalog and health changes
+	var notifier *webhook.Notifier
+	if config.Webhooks.Enabled {
+		notifier, err = newWebhookNotifier(config.Webhooks)
+		if err != nil {
+			log.Printf("failed to initialize webhooks: %v", err)
+			os.Exit(1)
+		}
+		log.Printf("📣 Sending catalog and health changes to %d webhook targets", len(config.Webhooks.Targets))
+	}
+
+	// Init service layer with HTTP client, discovery client, runtime settings, API keys, uptime store and catalog hub
+	service := service.NewService(httpClient, discoveryClient, settingsStore, apiKeys, uptimeStore, catalogHub)
+
+	go service.ReportHealth(ctx, healthReport)
+
+	if rateLimitSync != nil {
+		go service.SyncRateLimits(ctx, rateLimitSync)
+	}
+
+	if prober != nil {
+		go service.RunProber(ctx, prober)
+	}
+
+	go service.WatchCatalog(ctx)
+
+	if notifier != nil {
+		go notifier.Run(ctx, catalogHub)
+	}
 
-	// Init service layer with both HTTP client and discovery client
-	service := service.NewService(httpClient, discoveryClient)
+	// Init audit logger for admin actions
+	auditLogger, err := audit.NewLogger(config.Admin.AuditLogPath)
+	if err != nil {
+		log.Printf("failed to initialize audit logger: %v", err)
+		os.Exit(1)
+	}
+	defer auditLogger.Close()
+
+	// Init JWT verifier for the routed endpoints
+	var jwtVerifier *jwt.Verifier
+	if config.JWT.Enabled {
+		jwtVerifier, err = newJWTVerifier(config.JWT)
+		if err != nil {
+			log.Printf("failed to initialize JWT authentication: %v", err)
+			os.Exit(1)
+		}
+		log.Printf("🔐 JWT authentication enabled for /api/*")
+	}
 
 	// Init API layer
-	restApi := api.NewApi(config.App.Name, service)
+	restApi := api.NewApi(config.App.Name, config.Admin.Tokens, service, auditLogger, jwtVerifier, apiKeys, rateLimiter)
 
 	// Run rest server
-	runRestServer(config.App.Port, restApi)
+	app := runRestServer(config.App.Port, restApi)
 
-	// wait for ctrl + c to exit
+	// wait for ctrl + c (or docker stop) to exit
 	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
 
 	// block until a signal is received
 	<-ch
 
+	// Leave the catalog first, so no new traffic is sent here while shutting down
+	if err := discoveryClient.Deregister(registration.ID); err != nil {
+		log.Printf("⚠️ %v", err)
+	} else {
+		log.Printf("👋 Gateway deregistered from Consul")
+	}
+
+	// End the open catalog streams, then let in-flight requests finish
+	catalogHub.Close()
+	if err := app.ShutdownWithTimeout(shutdownTimeout); err != nil {
+		log.Printf("⚠️ failed to shut down rest server: %v", err)
+	}
+
 	log.Printf("end of program...")
 }
+
+// newHttpClient creates the client used to call services
+// Certificates are reloaded automatically when the files change
+func newHttpClient(upstream config.Upstream) (*http_adapter.Client, error) {
+	reloader, err := tlsutil.NewReloader(tlsutil.Files{
+		CertFile: upstream.CertFile,
+		KeyFile:  upstream.KeyFile,
+		CAFile:   upstream.CAFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load upstream TLS certificates: %w", err)
+	}
+
+	return http_adapter.NewClient(reloader.DialTLSContext(upstream.ServerName, upstream.InsecureSkipVerify)), nil
+}
+
+// newWebhookNotifier creates the notifier of the configured webhook targets
+func newWebhookNotifier(webhooksConfig config.Webhooks) (*webhook.Notifier, error) {
+	targets := make([]webhook.Target, 0, len(webhooksConfig.Targets))
+	for _, target := range webhooksConfig.Targets {
+		events := make([]catalog.EventType, 0, len(target.Events))
+		for _, event := range target.Events {
+			events = append(events, catalog.EventType(event))
+		}
+
+		targets = append(targets, webhook.Target{
+			URL:      target.URL,
+			Events:   events,
+			Services: target.Services,
+			Headers:  target.Headers,
+			Template: target.Template,
+		})
+	}
+
+	return webhook.New(targets, webhook.Options{
+		Cooldown:     webhooksConfig.Cooldown,
+		Retries:      webhooksConfig.Retries,
+		RetryBackoff: webhooksConfig.RetryBackoff,
+		Timeout:      webhooksConfig.Timeout,
+	})
+}
+
+// newJWTVerifier loads the configured JWT keys
+func newJWTVerifier(jwtConfig config.JWT) (*jwt.Verifier, error) {
+	options := jwt.Options{
+		Secret:   []byte(jwtConfig.Secret),
+		Issuer:   jwtConfig.Issuer,
+		Audience: jwtConfig.Audience,
+	}
+
+	if jwtConfig.SecretFile != "" {
+		secret, err := os.ReadFile(jwtConfig.SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT secret file: %w", err)
+		}
+		options.Secret = bytes.TrimSpace(secret)
+	}
+
+	if jwtConfig.PublicKeyFile != "" {
+		publicKey, err := jwt.LoadPublicKey(jwtConfig.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		options.PublicKey = publicKey
+	}
+
+	if jwtConfig.JWKSURL != "" {
+		jwks, err := jwt.NewJWKS(jwtConfig.JWKSURL)
+		if err != nil {
+			return nil, err
+		}
+		options.JWKS = jwks
+	}
+
+	return jwt.NewVerifier(options)
+}
+
+// gatewayRegistration describes how this gateway registers in Consul, so other tools and
+// the other gateway replicas can discover it
+func gatewayRegistration(app config.App) (consul.Registration, error) {
+	address := app.RegisterAddress
+	if address == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return consul.Registration{}, fmt.Errorf("failed to determine register address: %w", err)
+		}
+		address = hostname
+	}
+
+	return consul.Registration{
+		ID:       fmt.Sprintf("%s-%s-%d", app.Name, address, app.Port),
+		Name:     app.Name,
+		Address:  address,
+		Port:     app.Port,
+		Tags:     []string{service.GatewayTag},
+		Meta:     map[string]string{"protocol": "http"},
+		CheckTTL: gatewayCheckTTL,
+	}, nil
+}
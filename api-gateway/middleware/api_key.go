@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+
+	"api-gateway/util/apikey"
+	"api-gateway/util/settings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHeader is the request header carrying an API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyKey is the fiber.Ctx locals key holding the authenticated *apikey.Key
+const APIKeyKey = "api_key"
+
+// APIKeyAuth creates a middleware that authenticates requests carrying an X-API-Key header
+// and checks that the key may call the route's service. Requests without the header
+// are passed to fallback (e.g. JWT auth), or rejected when fallback is nil.
+func APIKeyAuth(store *apikey.Store, currentSettings func() *settings.Settings, fallback fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := c.Get(APIKeyHeader)
+		if token == "" {
+			if fallback != nil {
+				return fallback(c)
+			}
+
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "missing API key",
+				"usage": APIKeyHeader + ": <key>",
+			})
+		}
+
+		key, err := store.Authenticate(token)
+		if err != nil {
+			message := "invalid API key"
+			if errors.Is(err, apikey.ErrExpiredKey) || errors.Is(err, apikey.ErrRevokedKey) {
+				message = err.Error()
+			}
+
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": message,
+			})
+		}
+
+		route := currentSettings().Route(c.Params("serviceName"))
+		if !key.Allows(route.Service) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "API key is not allowed to call this service",
+				"service": route.Service,
+			})
+		}
+
+		c.Locals(APIKeyKey, key)
+
+		return c.Next()
+	}
+}
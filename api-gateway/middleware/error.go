@@ -13,6 +13,12 @@ func ErrorHandler() fiber.Handler {
 		// Forward to next handler
 		err := c.Next()
 
+		// Streamed responses are written after the handler returns; reading
+		// the body here would wait for the whole stream
+		if err == nil && c.Response().IsBodyStream() {
+			return nil
+		}
+
 		// Check if response was written
 		if len(c.Response().Body()) == 0 {
 			if err == nil {
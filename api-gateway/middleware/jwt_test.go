@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"api-gateway/util/jwt"
+	"api-gateway/util/settings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var testSecret = []byte("test-secret")
+
+// signHS256 returns an HS256 token carrying claims, given as a JSON object
+func signHS256(claims string) string {
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+
+	mac := hmac.New(sha256.New, testSecret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTAuth(t *testing.T) {
+	verifier, err := jwt.NewVerifier(jwt.Options{Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := settings.Defaults()
+	current.Routes["orders"] = settings.Route{RequiredScopes: []string{"orders:read"}}
+
+	app := fiber.New()
+	app.Get("/:serviceName", JWTAuth(verifier, func() *settings.Settings { return current }), func(c *fiber.Ctx) error {
+		return c.SendString(AuthHeaders(c)["X-Auth-Subject"])
+	})
+
+	exp := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	valid := signHS256(`{"sub":"alice","exp":` + exp + `}`)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string // Not sent when empty
+		wantStatus    int
+		wantBody      string // Substring of the response body
+		wantChallenge string // Substring of the WWW-Authenticate header
+	}{
+		{
+			name:          "valid token",
+			path:          "/users",
+			authorization: "Bearer " + valid,
+			wantStatus:    fiber.StatusOK,
+			wantBody:      "alice",
+		},
+		{
+			name:          "missing header",
+			path:          "/users",
+			wantStatus:    fiber.StatusUnauthorized,
+			wantBody:      "missing bearer token",
+			wantChallenge: `Bearer realm="api-gateway"`,
+		},
+		{
+			name:          "other scheme",
+			path:          "/users",
+			authorization: "Basic YWxpY2U6c2VjcmV0",
+			wantStatus:    fiber.StatusUnauthorized,
+			wantBody:      "missing bearer token",
+		},
+		{
+			name:          "lowercase scheme without a space",
+			path:          "/users",
+			authorization: "bearer" + valid,
+			wantStatus:    fiber.StatusUnauthorized,
+			wantBody:      "missing bearer token",
+		},
+		{
+			name:          "scheme without a token",
+			path:          "/users",
+			authorization: "Bearer ",
+			wantStatus:    fiber.StatusUnauthorized,
+			wantBody:      "missing bearer token",
+		},
+		{
+			name:          "not a JWT",
+			path:          "/users",
+			authorization: "Bearer not-a-jwt",
+			wantStatus:    fiber.StatusUnauthorized,
+			wantBody:      "malformed token",
+			wantChallenge: `error="invalid_token"`,
+		},
+		{
+			name:          "bad signature",
+			path:          "/users",
+			authorization: "Bearer " + valid[:strings.LastIndex(valid, ".")+1] + "c2lnbmF0dXJl",
+			wantStatus:    fiber.StatusUnauthorized,
+			wantBody:      "signature mismatch",
+			wantChallenge: `error="invalid_token"`,
+		},
+		{
+			name:          "missing required scope",
+			path:          "/orders",
+			authorization: "Bearer " + valid,
+			wantStatus:    fiber.StatusForbidden,
+			wantBody:      "insufficient scope",
+			wantChallenge: `error="insufficient_scope", scope="orders:read"`,
+		},
+		{
+			name:          "required scope",
+			path:          "/orders",
+			authorization: "Bearer " + signHS256(`{"sub":"alice","scope":"orders:read","exp":`+exp+`}`),
+			wantStatus:    fiber.StatusOK,
+			wantBody:      "alice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", test.path, nil)
+			if test.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, test.authorization)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := string(data)
+
+			if resp.StatusCode != test.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", resp.StatusCode, test.wantStatus, body)
+			}
+			if !strings.Contains(body, test.wantBody) {
+				t.Errorf("body = %s, want it to contain %q", body, test.wantBody)
+			}
+			if challenge := resp.Header.Get(fiber.HeaderWWWAuthenticate); !strings.Contains(challenge, test.wantChallenge) {
+				t.Errorf("WWW-Authenticate = %q, want it to contain %q", challenge, test.wantChallenge)
+			}
+		})
+	}
+}
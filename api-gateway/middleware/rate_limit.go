@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"api-gateway/util/apikey"
+	"api-gateway/util/jwt"
+	"api-gateway/util/metrics"
+	"api-gateway/util/ratelimit"
+	"api-gateway/util/settings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var rateLimitRequests = metrics.NewCounter(
+	"gateway_rate_limit_requests_total",
+	"Requests checked by the rate limiter, by route, service, result and limiting scope.",
+	"route", "service", "result", "scope",
+)
+
+// unconfiguredLabel replaces route and service labels that name nothing configured,
+// so requests to arbitrary paths cannot create new metric series
+const unconfiguredLabel = "unconfigured"
+
+// RateLimit creates a middleware that applies the global, per route and per service
+// token buckets from the runtime settings to each consumer. It must run after the
+// authentication middleware so consumers are identified by API key or JWT subject.
+func RateLimit(limiter *ratelimit.Limiter, currentSettings func() *settings.Settings) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		current := currentSettings()
+		routeName := c.Params("serviceName")
+		route := current.Route(routeName)
+		consumer := Consumer(c)
+
+		var checks []ratelimit.Check
+		addCheck := func(scope, name string, limit *settings.RateLimit) {
+			if limit != nil && limit.Enabled {
+				checks = append(checks, ratelimit.Check{
+					Scope:             scope,
+					Key:               scope + ":" + name + "|" + consumer,
+					RequestsPerSecond: limit.RequestsPerSecond,
+					Burst:             limit.Burst,
+				})
+			}
+		}
+
+		addCheck("global", "", &current.RateLimit)
+		addCheck("route", routeName, route.RateLimit)
+		addCheck("service", route.Service, current.Services[route.Service].RateLimit)
+
+		if len(checks) == 0 {
+			return c.Next()
+		}
+
+		result := limiter.Take(checks...)
+
+		c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+		routeLabel, serviceLabel := metricLabels(current, routeName, route.Service)
+
+		if !result.Allowed {
+			rateLimitRequests.Inc(routeLabel, serviceLabel, "limited", result.Scope)
+
+			retryAfter := seconds(result.RetryAfter)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":       "rate limit exceeded",
+				"scope":       result.Scope,
+				"retry_after": retryAfter,
+			})
+		}
+
+		rateLimitRequests.Inc(routeLabel, serviceLabel, "allowed", "")
+
+		return c.Next()
+	}
+}
+
+// metricLabels returns the route and service labels of a request: the configured
+// route and its service, or the service when only it has settings
+func metricLabels(current *settings.Settings, routeName, service string) (string, string) {
+	if _, ok := current.Routes[routeName]; ok {
+		return routeName, service
+	}
+
+	if _, ok := current.Services[service]; ok {
+		return unconfiguredLabel, service
+	}
+
+	return unconfiguredLabel, unconfiguredLabel
+}
+
+// Consumer identifies the caller of a request: the API key, the JWT subject
+// or, for anonymous requests, the client IP
+func Consumer(c *fiber.Ctx) string {
+	if key, ok := c.Locals(APIKeyKey).(*apikey.Key); ok {
+		return "key:" + key.ID
+	}
+
+	if claims, ok := c.Locals(ClaimsKey).(jwt.Claims); ok && claims.Subject() != "" {
+		return "sub:" + claims.Subject()
+	}
+
+	return "ip:" + c.IP()
+}
+
+// seconds rounds a duration up to whole seconds, as used by the rate limit headers
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
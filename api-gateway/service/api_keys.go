@@ -0,0 +1,68 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"api-gateway/util/apikey"
+)
+
+var (
+	ErrAPIKeysDisabled = errors.New("API keys are disabled")
+	ErrAPIKeyNotFound  = errors.New("API key not found")
+	ErrAPIKeyConflict  = errors.New("API key was changed concurrently, retry")
+	ErrAPIKeyRevoked   = errors.New("API key is revoked")
+
+	ErrInvalidAPIKeyRequest = errors.New("invalid API key request")
+)
+
+// APIKeyResponse is returned when a key is created or rotated.
+// Token is the plain key; it is not stored and cannot be shown again.
+type APIKeyResponse struct {
+	Key   *apikey.Key `json:"key"`
+	Token string      `json:"token"`
+}
+
+// loadAPIKey reads a key record and its modify index straight from Consul KV,
+// so updates never start from a stale cached copy
+func (s *Service) loadAPIKey(id string) (*apikey.Key, uint64, error) {
+	if s.apiKeys == nil {
+		return nil, 0, ErrAPIKeysDisabled
+	}
+
+	value, index, err := s.discoveryClient.GetKey(s.apiKeys.KVKey(id))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if value == nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, id)
+	}
+
+	var key apikey.Key
+	if err := json.Unmarshal(value, &key); err != nil {
+		return nil, 0, fmt.Errorf("invalid API key record %s: %w", id, err)
+	}
+
+	return &key, index, nil
+}
+
+// storeAPIKey writes a key record if it was not changed since index (0: new key)
+func (s *Service) storeAPIKey(key *apikey.Key, index uint64) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode API key %s: %w", key.ID, err)
+	}
+
+	ok, err := s.discoveryClient.PutKeyCAS(s.apiKeys.KVKey(key.ID), value, index)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrAPIKeyConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/settings"
+)
+
+// pickInstance selects one instance using the route's balancer strategy
+func (s *Service) pickInstance(route settings.Route, instances []consul.ServiceInstance) *consul.ServiceInstance {
+	switch route.Balancer {
+	case settings.StrategyRoundRobin:
+		counter, _ := s.roundRobin.LoadOrStore(route.Service, &atomic.Uint64{})
+		next := counter.(*atomic.Uint64).Add(1) - 1
+
+		return &instances[next%uint64(len(instances))]
+	case settings.StrategyWeighted:
+		return pickWeighted(route, s.routeLoadsFor(route, instances), instances)
+	case settings.StrategyNearest:
+		return s.pickNearest(route, s.routeLoadsFor(route, instances), instances)
+	default:
+		// Simple random load balancing
+		return &instances[rand.Intn(len(instances))]
+	}
+}
+
+// minLoadShare is the share of its weight a fully loaded instance keeps,
+// so it is never starved completely while it reports
+const minLoadShare = 0.05
+
+// pickWeighted selects an instance with a probability proportional to its weight.
+// Instances that report their load have their weight reduced by the smoothed load.
+// When no instance has a weight, every instance is equally likely.
+func pickWeighted(route settings.Route, loads *routeLoads, instances []consul.ServiceInstance) *consul.ServiceInstance {
+	weights := make([]float64, len(instances))
+	total := 0.0
+	for i, instance := range instances {
+		weights[i] = float64(instanceWeight(instance))
+		if instance.Load != nil {
+			load := loads.smooth(instance, time.Duration(route.LoadSmoothing))
+			weights[i] *= max(1-load, minLoadShare)
+		}
+		total += weights[i]
+	}
+
+	if total == 0 {
+		return &instances[rand.Intn(len(instances))]
+	}
+
+	target := rand.Float64() * total
+	for i := range instances {
+		target -= weights[i]
+		if target < 0 {
+			return &instances[i]
+		}
+	}
+
+	return &instances[len(instances)-1]
+}
+
+// instanceWeight is the Consul weight of an instance for its current check status.
+// Critical instances (only admitted by the "any" health policy) have no weight,
+// so they only receive traffic when no other instance does.
+func instanceWeight(instance consul.ServiceInstance) int {
+	switch instance.Status {
+	case "", "passing":
+		return max(instance.Weights.Passing, 0)
+	case "warning":
+		return max(instance.Weights.Warning, 0)
+	}
+
+	return 0
+}
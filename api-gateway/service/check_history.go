@@ -0,0 +1,73 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"api-gateway/client/consul"
+)
+
+// checkHistory remembers when the gateway saw health checks change status;
+// Consul only records the Raft index of the last change, not its time
+type checkHistory struct {
+	mu     sync.Mutex
+	checks map[string]*checkStatus // node/check id -> status
+}
+
+type checkStatus struct {
+	status     string
+	firstSeen  time.Time
+	lastChange *time.Time // nil while the check has the status it had when first seen
+}
+
+// observe records the current status of the checks of some instances
+// With all set, checks that are no longer registered are forgotten.
+func (h *checkHistory) observe(instances []consul.InstanceHealth, all bool) {
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.checks == nil {
+		h.checks = make(map[string]*checkStatus)
+	}
+
+	seen := make(map[string]bool)
+	for _, instance := range instances {
+		for _, check := range instance.Checks {
+			key := instance.Node + "/" + check.CheckID
+			seen[key] = true
+
+			known, ok := h.checks[key]
+			switch {
+			case !ok:
+				h.checks[key] = &checkStatus{status: check.Status, firstSeen: now}
+			case known.status != check.Status:
+				changed := now
+				known.status = check.Status
+				known.lastChange = &changed
+			}
+		}
+	}
+
+	if all {
+		for key := range h.checks {
+			if !seen[key] {
+				delete(h.checks, key)
+			}
+		}
+	}
+}
+
+// get returns what is known about a check
+func (h *checkHistory) get(node, checkID string) (checkStatus, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status, ok := h.checks[node+"/"+checkID]
+	if !ok {
+		return checkStatus{}, false
+	}
+
+	return *status, true
+}
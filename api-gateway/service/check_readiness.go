@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// watchStaleAfter is how long a KV watch may keep failing before its data counts as stale
+const watchStaleAfter = 30 * time.Second
+
+// ReadinessReport tells whether the gateway can route requests
+type ReadinessReport struct {
+	Ready  bool               `json:"ready"`
+	Status string             `json:"status"` // Worst status of the checks
+	Checks []HealthCheckState `json:"checks"`
+}
+
+// ServicesDetails lists the services that cannot be routed to
+type ServicesDetails struct {
+	Total     int      `json:"total"`
+	Unhealthy []string `json:"unhealthy"` // Services with no healthy instance
+}
+
+// CheckReadiness checks the dependencies the gateway needs to route requests:
+// the Consul leader, the freshness of the data watched in Consul KV (settings,
+// API keys, rate limit reports) and the services with no healthy instance.
+// The gateway is not ready when any check is critical.
+func (s *Service) CheckReadiness() *ReadinessReport {
+	report := &ReadinessReport{Status: HealthPassing}
+
+	add := func(check HealthCheckState) {
+		report.Checks = append(report.Checks, check)
+		if severity(check.Status) > severity(report.Status) {
+			report.Status = check.Status
+		}
+	}
+
+	// Consul leader; without it nothing else can be checked
+	leader, err := s.discoveryClient.Leader()
+	if err != nil {
+		add(HealthCheckState{Name: "consul", Status: HealthCritical, Output: err.Error()})
+		report.Ready = false
+
+		return report
+	}
+	add(HealthCheckState{Name: "consul", Status: HealthPassing, Output: "leader " + leader})
+
+	add(s.checkWatches())
+	add(s.checkServices())
+
+	report.Ready = report.Status != HealthCritical
+
+	return report
+}
+
+// checkWatches reports KV data that was never loaded (critical) or is going stale (warning)
+func (s *Service) checkWatches() HealthCheckState {
+	states := s.discoveryClient.WatchStates()
+	check := HealthCheckState{
+		Name:    "caches",
+		Status:  HealthPassing,
+		Output:  fmt.Sprintf("%d KV watches in sync", len(states)),
+		Details: states,
+	}
+
+	for _, state := range states {
+		switch {
+		case !state.Synced:
+			check.Status = HealthCritical
+			check.Output = fmt.Sprintf("%s was never loaded from Consul: %s", state.Prefix, state.LastError)
+
+			return check
+
+		case state.FailingSince != nil && time.Since(*state.FailingSince) > watchStaleAfter:
+			check.Status = HealthWarning
+			check.Output = fmt.Sprintf("%s is stale, failing since %s: %s",
+				state.Prefix, state.FailingSince.Format(time.RFC3339), state.LastError)
+		}
+	}
+
+	return check
+}
+
+// checkServices counts the services without a healthy instance in the catalog state
+// WatchCatalog keeps, so a probe costs no Consul request.
+// Some unhealthy services are a warning; the gateway cannot route at all
+// (critical) when every service is unhealthy.
+func (s *Service) checkServices() HealthCheckState {
+	instances, synced := s.catalog.Instances()
+	if !synced {
+		return HealthCheckState{Name: "services", Status: HealthCritical, Output: "the catalog was not read from Consul yet"}
+	}
+
+	// Service name -> whether one of its instances is passing
+	healthy := map[string]bool{}
+	for _, instance := range instances {
+		if instance.Service == "consul" || slices.Contains(instance.Tags, GatewayTag) {
+			continue
+		}
+
+		healthy[instance.Service] = healthy[instance.Service] || instance.Status == HealthPassing
+	}
+
+	details := ServicesDetails{Total: len(healthy), Unhealthy: []string{}}
+	for name, ok := range healthy {
+		if !ok {
+			details.Unhealthy = append(details.Unhealthy, name)
+		}
+	}
+	sort.Strings(details.Unhealthy)
+
+	check := HealthCheckState{
+		Name:    "services",
+		Status:  HealthPassing,
+		Output:  fmt.Sprintf("%d of %d services have no healthy instance", len(details.Unhealthy), details.Total),
+		Details: details,
+	}
+
+	switch {
+	case details.Total > 0 && len(details.Unhealthy) == details.Total:
+		check.Status = HealthCritical
+	case len(details.Unhealthy) > 0:
+		check.Status = HealthWarning
+	}
+
+	return check
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"api-gateway/util/apikey"
+)
+
+type CreateAPIKeyParam struct {
+	Owner     string
+	Services  []string
+	ExpiresAt *time.Time
+}
+
+// CreateAPIKey creates a new API key and stores its hash in Consul KV
+func (s *Service) CreateAPIKey(param *CreateAPIKeyParam) (*APIKeyResponse, error) {
+	if s.apiKeys == nil {
+		return nil, ErrAPIKeysDisabled
+	}
+
+	if strings.TrimSpace(param.Owner) == "" {
+		return nil, fmt.Errorf("%w: owner is required", ErrInvalidAPIKeyRequest)
+	}
+
+	if len(param.Services) == 0 {
+		return nil, fmt.Errorf("%w: at least one service is required (use \"*\" for all)", ErrInvalidAPIKeyRequest)
+	}
+
+	if param.ExpiresAt != nil && param.ExpiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidAPIKeyRequest)
+	}
+
+	key, token, err := apikey.New(param.Owner, param.Services, param.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storeAPIKey(key, 0); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔑 Created API key %s for %s (services: %s)", key.ID, key.Owner, strings.Join(key.Services, ","))
+
+	return &APIKeyResponse{Key: key.Public(), Token: token}, nil
+}
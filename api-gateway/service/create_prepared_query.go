@@ -0,0 +1,31 @@
+package service
+
+import (
+	"log"
+)
+
+type CreatePreparedQueryParam struct {
+	Query *PreparedQuery
+}
+
+// CreatePreparedQuery creates a prepared query in Consul and returns it with its new id
+func (s *Service) CreatePreparedQuery(param *CreatePreparedQueryParam) (*PreparedQuery, error) {
+	if err := validatePreparedQuery(param.Query); err != nil {
+		return nil, err
+	}
+
+	query := *param.Query
+	query.ID = ""
+
+	id, err := s.discoveryClient.CreatePreparedQuery(&query)
+	if err != nil {
+		return nil, err
+	}
+	query.ID = id
+
+	s.forgetPreparedQuery()
+
+	log.Printf("🧭 Created prepared query %s (%s) for %s", query.ID, query.Name, query.Service.Service)
+
+	return &query, nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"log"
+)
+
+type DeletePreparedQueryParam struct {
+	QueryID string
+}
+
+// DeletePreparedQuery deletes a prepared query. Routes still targeting it fail until
+// they are changed.
+func (s *Service) DeletePreparedQuery(param *DeletePreparedQueryParam) error {
+	if err := s.discoveryClient.DeletePreparedQuery(param.QueryID); err != nil {
+		return err
+	}
+
+	s.forgetPreparedQuery()
+
+	log.Printf("🗑️ Deleted prepared query %s", param.QueryID)
+
+	return nil
+}
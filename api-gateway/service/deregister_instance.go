@@ -0,0 +1,26 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"api-gateway/client/consul"
+)
+
+type DeregisterInstanceParam struct {
+	InstanceID string
+}
+
+// DeregisterInstance force-removes a (stale) service instance from the Consul catalog
+func (s *Service) DeregisterInstance(param *DeregisterInstanceParam) (*consul.CatalogInstance, error) {
+	log.Printf("🗑️ Deregistering instance: %s", param.InstanceID)
+
+	instance, err := s.discoveryClient.DeregisterInstance(param.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deregister instance: %w", err)
+	}
+
+	log.Printf("✅ Instance %s of %s removed from node %s", instance.ID, instance.Name, instance.Node)
+
+	return instance, nil
+}
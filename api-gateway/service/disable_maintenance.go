@@ -0,0 +1,24 @@
+package service
+
+import (
+	"fmt"
+	"log"
+)
+
+type DisableMaintenanceParam struct {
+	InstanceID string
+}
+
+// DisableMaintenance takes a service instance out of Consul maintenance mode
+func (s *Service) DisableMaintenance(param *DisableMaintenanceParam) error {
+	log.Printf("🚧 Disabling maintenance for instance: %s", param.InstanceID)
+
+	err := s.discoveryClient.DisableServiceMaintenance(param.InstanceID)
+	if err != nil {
+		return fmt.Errorf("failed to disable maintenance: %w", err)
+	}
+
+	log.Printf("✅ Instance %s is back in service", param.InstanceID)
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/settings"
+)
+
+// discover finds the healthy instances of a route's service. It queries the route's
+// datacenter (the local one by default) and fails over to the route's failover
+// datacenters, in order, while a datacenter has no healthy instance or cannot be reached.
+// Routes to a prepared query leave the lookup and failover to the query.
+func (s *Service) discover(route settings.Route) ([]consul.ServiceInstance, error) {
+	if route.Query != "" {
+		return s.resolveQuery(route)
+	}
+
+	instances, err := s.discoveryClient.DiscoverServiceIn(route.Service, route.Datacenter, route.Health)
+	if err == nil || len(route.Failover) == 0 {
+		return instances, err
+	}
+
+	failover, listErr := s.failoverDatacenters(route)
+	if listErr != nil {
+		return nil, errors.Join(err, listErr)
+	}
+
+	tried := []string{datacenterName(route.Datacenter)}
+	for _, datacenter := range failover {
+		log.Printf("🔀 %v, failing over to %s", err, datacenter)
+
+		instances, err = s.discoveryClient.DiscoverServiceIn(route.Service, datacenter, route.Health)
+		if err == nil {
+			return instances, nil
+		}
+		tried = append(tried, datacenter)
+	}
+
+	return nil, fmt.Errorf("no datacenter has healthy instances of %s (tried %s), last error: %w",
+		route.Service, strings.Join(tried, ", "), err)
+}
+
+// failoverDatacenters resolves the failover list of a route, expanding "*"
+// to every other known datacenter, nearest first
+func (s *Service) failoverDatacenters(route settings.Route) ([]string, error) {
+	if !slices.Contains(route.Failover, "*") {
+		return route.Failover, nil
+	}
+
+	known, err := s.discoveryClient.Datacenters()
+	if err != nil {
+		return nil, err
+	}
+
+	preferred := route.Datacenter
+	if preferred == "" {
+		if preferred, err = s.discoveryClient.LocalDatacenter(); err != nil {
+			return nil, err
+		}
+	}
+
+	var datacenters []string
+	add := func(datacenter string) {
+		if datacenter != preferred && !slices.Contains(datacenters, datacenter) {
+			datacenters = append(datacenters, datacenter)
+		}
+	}
+
+	for _, datacenter := range route.Failover {
+		if datacenter != "*" {
+			add(datacenter)
+			continue
+		}
+
+		for _, other := range known {
+			add(other)
+		}
+	}
+
+	return datacenters, nil
+}
+
+func datacenterName(datacenter string) string {
+	if datacenter == "" {
+		return "the local datacenter"
+	}
+
+	return datacenter
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"fmt"
+	"log"
+)
+
+type EnableMaintenanceParam struct {
+	InstanceID string
+	Reason     string
+}
+
+// EnableMaintenance drains a service instance by putting it into Consul maintenance mode
+func (s *Service) EnableMaintenance(param *EnableMaintenanceParam) error {
+	log.Printf("🚧 Enabling maintenance for instance: %s (reason: %s)", param.InstanceID, param.Reason)
+
+	err := s.discoveryClient.EnableServiceMaintenance(param.InstanceID, param.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to enable maintenance: %w", err)
+	}
+
+	log.Printf("✅ Instance %s is now in maintenance mode", param.InstanceID)
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Health statuses, matching the Consul check statuses
+const (
+	HealthPassing  = "passing"
+	HealthWarning  = "warning"
+	HealthCritical = "critical"
+)
+
+// Upstream error rates at which the gateway reports itself degraded,
+// once enough requests were made in the last minute to judge
+const (
+	upstreamWarningRate  = 0.2
+	upstreamCriticalRate = 0.5
+	upstreamMinRequests  = 10
+)
+
+// HealthReport is the result of the gateway's internal health evaluation
+type HealthReport struct {
+	Status string             `json:"status"`
+	Checks []HealthCheckState `json:"checks"`
+}
+
+// HealthCheckState is the result of one health check
+type HealthCheckState struct {
+	Name    string      `json:"name"`
+	Status  string      `json:"status"`
+	Output  string      `json:"output"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Output summarizes the report in one line, as shown in the Consul UI
+func (r *HealthReport) Output() string {
+	parts := make([]string, 0, len(r.Checks))
+	for _, check := range r.Checks {
+		parts = append(parts, fmt.Sprintf("%s: %s (%s)", check.Name, check.Status, check.Output))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// EvaluateHealth checks that Consul is reachable and that calls to services mostly succeed.
+// The worst check decides the overall status.
+func (s *Service) EvaluateHealth() *HealthReport {
+	report := &HealthReport{Status: HealthPassing}
+
+	add := func(check HealthCheckState) {
+		report.Checks = append(report.Checks, check)
+		if severity(check.Status) > severity(report.Status) {
+			report.Status = check.Status
+		}
+	}
+
+	// Consul reachability
+	if leader, err := s.discoveryClient.Leader(); err != nil {
+		add(HealthCheckState{Name: "consul", Status: HealthCritical, Output: err.Error()})
+	} else {
+		add(HealthCheckState{Name: "consul", Status: HealthPassing, Output: "leader " + leader})
+	}
+
+	// Upstream error rate
+	requests, errors := s.upstream.totals()
+	check := HealthCheckState{
+		Name:   "upstream",
+		Status: HealthPassing,
+		Output: fmt.Sprintf("%d of %d requests failed in the last minute", errors, requests),
+	}
+	if requests >= upstreamMinRequests {
+		rate := float64(errors) / float64(requests)
+		switch {
+		case rate >= upstreamCriticalRate:
+			check.Status = HealthCritical
+		case rate >= upstreamWarningRate:
+			check.Status = HealthWarning
+		}
+	}
+	add(check)
+
+	return report
+}
+
+func severity(status string) int {
+	switch status {
+	case HealthWarning:
+		return 1
+	case HealthCritical:
+		return 2
+	}
+
+	return 0
+}
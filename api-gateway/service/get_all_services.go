@@ -3,13 +3,22 @@ package service
 import (
 	"fmt"
 	"log"
+
+	"api-gateway/client/consul"
 )
 
+// ErrUnknownDatacenter is returned when Consul has no route to the requested datacenter
+var ErrUnknownDatacenter = consul.ErrUnknownDatacenter
+
+type GetAllServicesParam struct {
+	Datacenter string // Empty for the local datacenter
+}
+
 // GetAllAvailableServices returns all services registered in Consul
-func (s *Service) GetAllAvailableServices() (map[string][]string, error) {
-	log.Printf("🔍 Discovering all available services")
+func (s *Service) GetAllAvailableServices(param *GetAllServicesParam) (map[string][]string, error) {
+	log.Printf("🔍 Discovering all available services in %s", datacenterName(param.Datacenter))
 
-	services, err := s.discoveryClient.GetAllServices()
+	services, err := s.discoveryClient.GetAllServicesIn(param.Datacenter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all services: %w", err)
 	}
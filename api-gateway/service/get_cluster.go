@@ -0,0 +1,20 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"api-gateway/client/consul"
+)
+
+// GetCluster returns the datacenter, Raft leader and peers, and agent members of the Consul cluster
+func (s *Service) GetCluster() (*consul.ClusterInfo, error) {
+	log.Printf("🔍 Reading Consul cluster state")
+
+	cluster, err := s.discoveryClient.ClusterInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster state: %w", err)
+	}
+
+	return cluster, nil
+}
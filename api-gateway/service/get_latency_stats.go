@@ -0,0 +1,43 @@
+package service
+
+import (
+	"time"
+
+	"api-gateway/util/latency"
+)
+
+type GetLatencyStatsParam struct {
+	Window      time.Duration // Zero or longer than the tracked window means the whole window
+	ServiceName string        // Empty for all services
+}
+
+// GetLatencyStatsResponse holds windowed latency statistics per service and instance
+type GetLatencyStatsResponse struct {
+	Window   string                 `json:"window"`
+	Services []latency.ServiceStats `json:"services"`
+}
+
+// GetLatencyStats returns the latency statistics of the requests made to services,
+// both proxied requests and pings
+func (s *Service) GetLatencyStats(param *GetLatencyStatsParam) *GetLatencyStatsResponse {
+	window := param.Window
+	if window <= 0 || window > s.latency.Window() {
+		window = s.latency.Window()
+	}
+
+	services := s.latency.Snapshot(window)
+	if param.ServiceName != "" {
+		filtered := []latency.ServiceStats{}
+		for _, stats := range services {
+			if stats.Service == param.ServiceName {
+				filtered = append(filtered, stats)
+			}
+		}
+		services = filtered
+	}
+
+	return &GetLatencyStatsResponse{
+		Window:   window.String(),
+		Services: services,
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"api-gateway/client/consul"
+)
+
+// GetNodes returns every node of the catalog with its services and node-level health checks
+func (s *Service) GetNodes() ([]consul.NodeInfo, error) {
+	log.Printf("🔍 Listing Consul nodes")
+
+	nodes, err := s.discoveryClient.Nodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	log.Printf("✅ Found %d nodes", len(nodes))
+
+	return nodes, nil
+}
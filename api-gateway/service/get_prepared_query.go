@@ -0,0 +1,10 @@
+package service
+
+type GetPreparedQueryParam struct {
+	QueryID string
+}
+
+// GetPreparedQuery returns a prepared query definition by id
+func (s *Service) GetPreparedQuery(param *GetPreparedQueryParam) (*PreparedQuery, error) {
+	return s.discoveryClient.GetPreparedQuery(param.QueryID)
+}
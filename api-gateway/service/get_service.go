@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"api-gateway/client/consul"
+)
+
+// ErrServiceNotFound is returned when no instance of a service is registered
+var ErrServiceNotFound = errors.New("service not found")
+
+type GetServiceParam struct {
+	ServiceName string
+}
+
+// ServiceDetails lists the registered instances of a service
+type ServiceDetails struct {
+	Service   string            `json:"service"`
+	Instances []InstanceDetails `json:"instances"`
+}
+
+// InstanceDetails is a registered instance with its health
+type InstanceDetails struct {
+	ID          string            `json:"id"`
+	Address     string            `json:"address"`
+	Port        int               `json:"port"`
+	Tags        []string          `json:"tags"`
+	Meta        map[string]string `json:"meta"`
+	Weights     consul.Weights    `json:"weights"`
+	Node        string            `json:"node"`
+	NodeAddress string            `json:"node_address"`
+	Datacenter  string            `json:"datacenter"`
+	Status      string            `json:"status"`         // Aggregated status of the instance and node checks
+	Load        *float64          `json:"load,omitempty"` // Self-reported load, when the instance reports it
+}
+
+// GetService returns every registered instance of a service, healthy or not
+func (s *Service) GetService(param *GetServiceParam) (*ServiceDetails, error) {
+	log.Printf("🔍 Looking up service: %s", param.ServiceName)
+
+	instances, err := s.serviceHealth(param.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &ServiceDetails{
+		Service:   param.ServiceName,
+		Instances: make([]InstanceDetails, 0, len(instances)),
+	}
+	for _, health := range instances {
+		details.Instances = append(details.Instances, InstanceDetails{
+			ID:          health.Instance.ID,
+			Address:     health.Instance.Address,
+			Port:        health.Instance.Port,
+			Tags:        health.Instance.Tags,
+			Meta:        health.Instance.Meta,
+			Weights:     health.Instance.Weights,
+			Node:        health.Node,
+			NodeAddress: health.NodeAddress,
+			Datacenter:  health.Datacenter,
+			Status:      health.Status,
+			Load:        health.Instance.Load,
+		})
+	}
+
+	return details, nil
+}
+
+// serviceHealth returns every instance of a service and records the status of their checks
+func (s *Service) serviceHealth(serviceName string) ([]consul.InstanceHealth, error) {
+	instances, err := s.discoveryClient.GetServiceHealth(serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instances of %s: %w", serviceName, err)
+	}
+
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("%w: no instance of %s is registered", ErrServiceNotFound, serviceName)
+	}
+
+	s.checks.observe(instances, false)
+
+	return instances, nil
+}
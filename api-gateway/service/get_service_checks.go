@@ -0,0 +1,75 @@
+package service
+
+import (
+	"log"
+	"time"
+)
+
+type GetServiceChecksParam struct {
+	ServiceName string
+}
+
+// ServiceChecks lists the health checks of every instance of a service
+type ServiceChecks struct {
+	Service string         `json:"service"`
+	Checks  []CheckDetails `json:"checks"`
+}
+
+// CheckDetails is a Consul health check of an instance, or of the node it runs on
+type CheckDetails struct {
+	Instance    string     `json:"instance"`
+	Node        string     `json:"node"`
+	CheckID     string     `json:"check_id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Scope       string     `json:"scope"` // service or node
+	Status      string     `json:"status"`
+	Output      string     `json:"output"`
+	Notes       string     `json:"notes,omitempty"`
+	ModifyIndex uint64     `json:"modify_index"`
+	LastChange  *time.Time `json:"last_change,omitempty"` // When the gateway saw the status change; absent if it never did
+	FirstSeen   *time.Time `json:"first_seen,omitempty"`  // When the gateway first saw the check
+}
+
+// GetServiceChecks returns every health check of every instance of a service,
+// with the time the gateway saw the status change last
+func (s *Service) GetServiceChecks(param *GetServiceChecksParam) (*ServiceChecks, error) {
+	log.Printf("🔍 Looking up health checks of service: %s", param.ServiceName)
+
+	instances, err := s.serviceHealth(param.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := &ServiceChecks{Service: param.ServiceName, Checks: []CheckDetails{}}
+	for _, health := range instances {
+		for _, check := range health.Checks {
+			details := CheckDetails{
+				Instance:    health.Instance.ID,
+				Node:        health.Node,
+				CheckID:     check.CheckID,
+				Name:        check.Name,
+				Type:        check.Type,
+				Scope:       "service",
+				Status:      check.Status,
+				Output:      check.Output,
+				Notes:       check.Notes,
+				ModifyIndex: check.ModifyIndex,
+			}
+
+			if check.ServiceID == "" {
+				details.Scope = "node"
+			}
+
+			if known, ok := s.checks.get(health.Node, check.CheckID); ok {
+				firstSeen := known.firstSeen
+				details.FirstSeen = &firstSeen
+				details.LastChange = known.lastChange
+			}
+
+			checks.Checks = append(checks.Checks, details)
+		}
+	}
+
+	return checks, nil
+}
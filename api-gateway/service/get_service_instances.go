@@ -0,0 +1,26 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"api-gateway/client/consul"
+)
+
+type GetServiceInstancesParam struct {
+	ServiceName string
+}
+
+// GetServiceInstances returns all instances of a service with their health status
+func (s *Service) GetServiceInstances(param *GetServiceInstancesParam) ([]consul.InstanceHealth, error) {
+	log.Printf("🔍 Looking up instances of service: %s", param.ServiceName)
+
+	instances, err := s.discoveryClient.GetServiceHealth(param.ServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instances of %s: %w", param.ServiceName, err)
+	}
+
+	log.Printf("✅ Found %d instances of %s", len(instances), param.ServiceName)
+
+	return instances, nil
+}
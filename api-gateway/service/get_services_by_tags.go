@@ -0,0 +1,42 @@
+package service
+
+import (
+	"log"
+	"slices"
+)
+
+type GetServicesByTagsParam struct {
+	Tags []string
+}
+
+// GetServicesByTags returns the registered services carrying all of the given tags
+// Without tags it behaves like GetAllAvailableServices
+func (s *Service) GetServicesByTags(param *GetServicesByTagsParam) (map[string][]string, error) {
+	services, err := s.GetAllAvailableServices(&GetAllServicesParam{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(param.Tags) == 0 {
+		return services, nil
+	}
+
+	filtered := make(map[string][]string)
+	for serviceName, serviceTags := range services {
+		matches := true
+		for _, tag := range param.Tags {
+			if !slices.Contains(serviceTags, tag) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			filtered[serviceName] = serviceTags
+		}
+	}
+
+	log.Printf("✅ %d services match tags %v", len(filtered), param.Tags)
+
+	return filtered, nil
+}
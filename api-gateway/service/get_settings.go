@@ -0,0 +1,10 @@
+package service
+
+import (
+	"api-gateway/util/settings"
+)
+
+// GetSettings returns the runtime settings currently in effect
+func (s *Service) GetSettings() *settings.Settings {
+	return s.settings.Current()
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"api-gateway/util/uptime"
+)
+
+// ErrProberDisabled is returned when uptime is requested but the prober is not running
+var ErrProberDisabled = errors.New("prober is disabled")
+
+type GetUptimeParam struct {
+	Window      time.Duration // Zero or longer than the retention means the whole retention
+	ServiceName string        // Empty for all services
+}
+
+// GetUptimeResponse holds the uptime and incidents of services over a window
+type GetUptimeResponse struct {
+	Window   string                 `json:"window"`
+	Services []uptime.ServiceUptime `json:"services"`
+}
+
+// GetUptime summarizes the probe results of a window into uptime percentages and incidents
+func (s *Service) GetUptime(param *GetUptimeParam) (*GetUptimeResponse, error) {
+	if s.uptime == nil {
+		return nil, ErrProberDisabled
+	}
+
+	window := param.Window
+	if window <= 0 || window > s.uptime.Retention() {
+		window = s.uptime.Retention()
+	}
+
+	now := time.Now()
+	results := s.uptime.Since(now.Add(-window))
+
+	if param.ServiceName != "" {
+		filtered := []uptime.Result{}
+		for _, result := range results {
+			if result.Service == param.ServiceName {
+				filtered = append(filtered, result)
+			}
+		}
+		results = filtered
+	}
+
+	return &GetUptimeResponse{
+		Window:   window.String(),
+		Services: uptime.Summarize(results, now),
+	}, nil
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"api-gateway/util/apikey"
+)
+
+// ListAPIKeys returns every API key known to the gateway, without hashes
+func (s *Service) ListAPIKeys() ([]*apikey.Key, error) {
+	if s.apiKeys == nil {
+		return nil, ErrAPIKeysDisabled
+	}
+
+	return s.apiKeys.List(), nil
+}
@@ -0,0 +1,6 @@
+package service
+
+// ListPreparedQueries returns every prepared query defined in Consul
+func (s *Service) ListPreparedQueries() ([]*PreparedQuery, error) {
+	return s.discoveryClient.ListPreparedQueries()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"cmp"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/settings"
+)
+
+// rttCacheTTL is how long node round-trip times are reused; coordinates change slowly
+const rttCacheTTL = 10 * time.Second
+
+// cachedRTTs are the round-trip times from the local agent to the nodes of its datacenter
+type cachedRTTs struct {
+	rtts    map[string]time.Duration
+	err     error
+	expires time.Time
+}
+
+// pickNearest selects an instance among the ones closest to the gateway's Consul agent by
+// network round-trip time, weighted as pickWeighted does. Instances within the tolerance
+// of the closest one count as equally near. While all of them report a load at or above
+// the spill-over load, the next closest instances take traffic too.
+// Without network coordinates it falls back to weighted balancing over every instance.
+func (s *Service) pickNearest(route settings.Route, loads *routeLoads, instances []consul.ServiceInstance) *consul.ServiceInstance {
+	rtts, err := s.nodeRTTs()
+	if err != nil {
+		return pickWeighted(route, loads, instances)
+	}
+
+	// Instances on nodes without a coordinate (e.g. in another datacenter) come last
+	rtt := func(instance consul.ServiceInstance) time.Duration {
+		if value, ok := rtts[instance.Node]; ok {
+			return value
+		}
+		return math.MaxInt64
+	}
+
+	sorted := slices.Clone(instances)
+	slices.SortStableFunc(sorted, func(a, b consul.ServiceInstance) int {
+		return cmp.Compare(rtt(a), rtt(b))
+	})
+
+	nearest := *route.Nearest
+	candidates := 0
+	for candidates < len(sorted) {
+		// Widen the candidates to every instance within the tolerance of the next closest one
+		limit := rtt(sorted[candidates]) + time.Duration(nearest.Tolerance)
+		if limit < 0 {
+			limit = math.MaxInt64 // Overflowed: the next instance has no coordinate
+		}
+		for candidates < len(sorted) && rtt(sorted[candidates]) <= limit {
+			candidates++
+		}
+
+		if !saturated(route, loads, sorted[:candidates], nearest.SpillOverLoad) {
+			break
+		}
+	}
+
+	return pickWeighted(route, loads, sorted[:candidates])
+}
+
+// saturated reports whether every instance reports a smoothed load at or above spillOverLoad.
+// Instances that do not report their load are never saturated.
+func saturated(route settings.Route, loads *routeLoads, instances []consul.ServiceInstance, spillOverLoad float64) bool {
+	for _, instance := range instances {
+		if instance.Load == nil {
+			return false
+		}
+
+		if loads.smooth(instance, time.Duration(route.LoadSmoothing)) < spillOverLoad {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nodeRTTs returns the round-trip times from the local agent to the nodes of its datacenter,
+// read from Consul at most every rttCacheTTL. Failures are cached too, so a missing
+// coordinate does not cost a Consul request per ping.
+func (s *Service) nodeRTTs() (map[string]time.Duration, error) {
+	if cached := s.rtts.Load(); cached != nil && time.Now().Before(cached.expires) {
+		return cached.rtts, cached.err
+	}
+
+	rtts, err := s.discoveryClient.NodeRTTs()
+	if err != nil {
+		log.Printf("⚠️ Nearest balancing falls back to weighted: %v", err)
+	}
+
+	s.rtts.Store(&cachedRTTs{rtts: rtts, err: err, expires: time.Now().Add(rttCacheTTL)})
+
+	return rtts, err
+}
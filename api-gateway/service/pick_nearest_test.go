@@ -0,0 +1,139 @@
+package service
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/config"
+	"api-gateway/util/settings"
+)
+
+// nodeCoordinates places every node of the fake Consul on a line, by its
+// distance in seconds from the gateway's node; "remote" has no coordinate
+var nodeCoordinates = map[string]float64{
+	"gw-node": 0,
+	"near-1":  0.001,
+	"near-2":  0.0025, // Within the tolerance of near-1
+	"far":     0.010,
+}
+
+// newNearestService returns a service whose Consul agent runs on localNode of a fake
+// Consul serving fixed network coordinates
+func newNearestService(t *testing.T, localNode string) *Service {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/agent/self", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"Config": map[string]any{"Datacenter": "dc1", "NodeName": localNode}})
+	})
+	mux.HandleFunc("/v1/coordinate/nodes", func(w http.ResponseWriter, r *http.Request) {
+		var entries []map[string]any
+		for node, distance := range nodeCoordinates {
+			vec := make([]float64, 8)
+			vec[0] = distance
+			entries = append(entries, map[string]any{
+				"Node":  node,
+				"Coord": map[string]any{"Vec": vec, "Error": 0.1, "Adjustment": 0, "Height": 0},
+			})
+		}
+		writeJSON(w, entries)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	discoveryClient, err := consul.NewDiscoveryClient(config.Consul{Host: host, Port: portNumber, Scheme: "http"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewService(nil, discoveryClient, nil, nil, nil, nil)
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func TestPickNearest(t *testing.T) {
+	route := settings.Route{
+		Service:  "service-a",
+		Balancer: settings.StrategyNearest,
+		Nearest:  &settings.Nearest{Tolerance: settings.Duration(2 * time.Millisecond), SpillOverLoad: 0.8},
+	}
+
+	instance := func(node string, load ...float64) consul.ServiceInstance {
+		instance := consul.ServiceInstance{ID: node, Node: node, Weights: consul.Weights{Passing: 1, Warning: 1}}
+		if len(load) > 0 {
+			instance.Load = &load[0]
+		}
+		return instance
+	}
+
+	tests := []struct {
+		name      string
+		localNode string
+		instances []consul.ServiceInstance
+		want      []string // Nodes that must receive traffic, and no others
+	}{
+		{
+			name:      "nearest instance only",
+			localNode: "gw-node",
+			instances: []consul.ServiceInstance{instance("far"), instance("near-1"), instance("remote")},
+			want:      []string{"near-1"},
+		},
+		{
+			name:      "instances within the tolerance count as equally near",
+			localNode: "gw-node",
+			instances: []consul.ServiceInstance{instance("far"), instance("near-2"), instance("near-1")},
+			want:      []string{"near-1", "near-2"},
+		},
+		{
+			name:      "nearest tier below the spill-over load keeps all traffic",
+			localNode: "gw-node",
+			instances: []consul.ServiceInstance{instance("far"), instance("near-2", 0.5), instance("near-1", 0.9)},
+			want:      []string{"near-1", "near-2"},
+		},
+		{
+			name:      "saturated nearest tier spills over to the next closest",
+			localNode: "gw-node",
+			instances: []consul.ServiceInstance{instance("far"), instance("near-2", 0.8), instance("near-1", 0.9), instance("remote")},
+			want:      []string{"near-1", "near-2", "far"},
+		},
+		{
+			name:      "local node without a coordinate falls back to weighted",
+			localNode: "remote",
+			instances: []consul.ServiceInstance{instance("far"), instance("near-1"), instance("remote")},
+			want:      []string{"far", "near-1", "remote"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newNearestService(t, test.localNode)
+
+			picked := map[string]int{}
+			for range 1000 {
+				picked[s.pickInstance(route, test.instances).Node]++
+			}
+
+			for _, node := range test.want {
+				if picked[node] == 0 {
+					t.Errorf("%s received no traffic, picks: %v", node, picked)
+				}
+			}
+			if len(picked) != len(test.want) {
+				t.Errorf("picks = %v, want only %v", picked, test.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"log"
+	"slices"
 )
 
 // PingAllServices discovers and pings all available services
@@ -10,7 +11,7 @@ func (s *Service) PingAllServices() (map[string]*PingServiceResponse, error) {
 	log.Printf("🔍 Discovering and pinging all services")
 
 	// Get all services
-	services, err := s.GetAllAvailableServices()
+	services, err := s.GetAllAvailableServices(&GetAllServicesParam{})
 	if err != nil {
 		return nil, err
 	}
@@ -18,12 +19,17 @@ func (s *Service) PingAllServices() (map[string]*PingServiceResponse, error) {
 	results := make(map[string]*PingServiceResponse)
 
 	// Ping each service
-	for serviceName := range services {
+	for serviceName, tags := range services {
 		// Skip consul service itself
 		if serviceName == "consul" {
 			continue
 		}
 
+		// Skip the gateway replicas registered in cluster mode
+		if slices.Contains(tags, GatewayTag) {
+			continue
+		}
+
 		response, err := s.PingService(&PingServiceParam{ServiceName: serviceName})
 		if err != nil {
 			log.Printf("❌ Failed to ping %s: %v", serviceName, err)
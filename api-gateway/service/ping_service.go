@@ -1,14 +1,19 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"api-gateway/client/consul"
+	"api-gateway/client/http_adapter"
+	"api-gateway/util/latency"
 )
 
 type PingServiceParam struct {
 	ServiceName string
+	Headers     map[string]string // Extra headers forwarded to the service (e.g. authenticated caller)
 }
 
 // PingServiceResponse represents the response from a ping request
@@ -16,42 +21,100 @@ type PingServiceResponse struct {
 	Service     string                  `json:"service"`
 	Message     string                  `json:"message"`
 	Instance    *consul.ServiceInstance `json:"instance"`
+	Datacenter  string                  `json:"datacenter,omitempty"` // Datacenter that served the request
 	StatusCode  int                     `json:"status_code"`
+	Error       string                  `json:"error,omitempty"`         // Why the instance could not be reached, status_code is 0 then
+	LatencyMs   float64                 `json:"latency_ms"`              // Round-trip time
+	Timing      *PingTiming             `json:"timing,omitempty"`        // Breakdown of the round trip
+	Stats       *latency.Stats          `json:"latency_stats,omitempty"` // Rolling statistics of the instance
 	RawResponse map[string]interface{}  `json:"raw_response"`
 }
 
+// PingTiming breaks down the round-trip time of a ping
+// DNS, connect and TLS are zero when a kept-alive connection was reused
+type PingTiming struct {
+	DNSMs            float64 `json:"dns_ms"`
+	ConnectMs        float64 `json:"connect_ms"`
+	TLSMs            float64 `json:"tls_ms"`
+	FirstByteMs      float64 `json:"first_byte_ms"`
+	TotalMs          float64 `json:"total_ms"`
+	ReusedConnection bool    `json:"reused_connection"`
+}
+
 // PingService discovers and pings a specific service
 // This is the core function that demonstrates dynamic service discovery
 func (s *Service) PingService(param *PingServiceParam) (*PingServiceResponse, error) {
-	log.Printf("🔍 Discovering service: %s", param.ServiceName)
+	// Read the settings once, so a concurrent settings update cannot change them mid-request
+	route := s.settings.Current().Route(param.ServiceName)
+
+	log.Printf("🔍 Discovering service: %s", route.Service)
 
 	// 1. Discover the service using Consul
-	instance, err := s.discoveryClient.DiscoverServiceWithLoadBalancing(param.ServiceName)
+	instances, err := s.discover(route)
 	if err != nil {
-		return nil, fmt.Errorf("service discovery failed for %s: %w", param.ServiceName, err)
+		return nil, fmt.Errorf("service discovery failed for %s: %w", route.Service, err)
 	}
 
-	log.Printf("✅ Found service instance: %s at %s:%d", instance.Name, instance.Address, instance.Port)
+	instance := s.pickInstance(route, instances)
+
+	log.Printf("✅ Found service instance: %s at %s:%d in %s", instance.Name, instance.Address, instance.Port, instance.Datacenter)
 
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(route.Timeout))
+	defer cancel()
+
+	return s.pingInstance(ctx, param.ServiceName, instance, param.Headers)
+}
+
+// pingInstance pings one specific instance of a service
+func (s *Service) pingInstance(ctx context.Context, serviceName string, instance *consul.ServiceInstance, headers map[string]string) (*PingServiceResponse, error) {
 	// 2. Build the URL dynamically
-	url := fmt.Sprintf("http://%s:%d/ping", instance.Address, instance.Port)
+	url := fmt.Sprintf("%s://%s:%d/ping", instance.Scheme(), instance.Address, instance.Port)
 
 	log.Printf("🌐 Making request to: %s", url)
 
 	// 3. Make the HTTP request
-	response, err := s.httpClient.Get(url)
+	started := time.Now()
+	response, err := s.httpClient.Get(ctx, url, headers)
+	rtt := time.Since(started)
+
+	failed := err != nil || response.StatusCode >= 500
+	s.upstream.record(failed)
+	s.latency.Record(instance.Name, instance.ID, rtt, failed)
+
 	if err != nil {
-		return nil, fmt.Errorf("failed to ping service %s at %s: %w", param.ServiceName, url, err)
+		return nil, fmt.Errorf("failed to ping service %s at %s: %w", serviceName, url, err)
 	}
 
 	log.Printf("📨 Received response with status: %d", response.StatusCode)
 
 	// 4. Return structured response
 	return &PingServiceResponse{
-		Service:     param.ServiceName,
-		Message:     fmt.Sprintf("Successfully pinged %s", param.ServiceName),
+		Service:     serviceName,
+		Message:     fmt.Sprintf("Successfully pinged %s", serviceName),
 		Instance:    instance,
+		Datacenter:  instance.Datacenter,
 		StatusCode:  response.StatusCode,
+		LatencyMs:   latency.Milliseconds(rtt),
+		Timing:      newPingTiming(response.Timing),
+		Stats:       s.instanceStats(instance),
 		RawResponse: response.Body,
 	}, nil
 }
+
+// instanceStats returns the rolling latency statistics of an instance
+func (s *Service) instanceStats(instance *consul.ServiceInstance) *latency.Stats {
+	stats := s.latency.Instance(instance.Name, instance.ID)
+
+	return &stats
+}
+
+func newPingTiming(timing http_adapter.Timing) *PingTiming {
+	return &PingTiming{
+		DNSMs:            latency.Milliseconds(timing.DNS),
+		ConnectMs:        latency.Milliseconds(timing.Connect),
+		TLSMs:            latency.Milliseconds(timing.TLS),
+		FirstByteMs:      latency.Milliseconds(timing.FirstByte),
+		TotalMs:          latency.Milliseconds(timing.Total),
+		ReusedConnection: timing.ConnReused,
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"api-gateway/client/consul"
+)
+
+// ErrInstanceNotFound is returned when no (healthy) instance has the requested id
+var ErrInstanceNotFound = consul.ErrInstanceNotFound
+
+type PingServiceInstanceParam struct {
+	ServiceName string
+	InstanceID  string
+	Headers     map[string]string // Extra headers forwarded to the service (e.g. authenticated caller)
+}
+
+// PingServiceInstance pings one specific healthy instance of a service,
+// bypassing the load balancer
+func (s *Service) PingServiceInstance(param *PingServiceInstanceParam) (*PingServiceResponse, error) {
+	route := s.settings.Current().Route(param.ServiceName)
+
+	log.Printf("🔍 Discovering instance %s of service: %s", param.InstanceID, route.Service)
+
+	instances, err := s.discover(route)
+	if err != nil {
+		return nil, fmt.Errorf("service discovery failed for %s: %w", route.Service, err)
+	}
+
+	for i := range instances {
+		if instances[i].ID != param.InstanceID {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(route.Timeout))
+		defer cancel()
+
+		return s.pingInstance(ctx, param.ServiceName, &instances[i], param.Headers)
+	}
+
+	return nil, fmt.Errorf("%w: no healthy instance %s of %s", ErrInstanceNotFound, param.InstanceID, route.Service)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"api-gateway/util/latency"
+)
+
+type PingServiceInstancesParam struct {
+	ServiceName string
+	Headers     map[string]string // Extra headers forwarded to the service (e.g. authenticated caller)
+}
+
+// PingServiceInstances pings every healthy instance of a service in parallel
+// Results are returned in the order Consul reported the instances
+func (s *Service) PingServiceInstances(param *PingServiceInstancesParam) ([]*PingServiceResponse, error) {
+	route := s.settings.Current().Route(param.ServiceName)
+
+	log.Printf("🔍 Discovering all instances of service: %s", route.Service)
+
+	instances, err := s.discover(route)
+	if err != nil {
+		return nil, fmt.Errorf("service discovery failed for %s: %w", route.Service, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(route.Timeout))
+	defer cancel()
+
+	results := make([]*PingServiceResponse, len(instances))
+
+	var wg sync.WaitGroup
+	for i := range instances {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			instance := &instances[i]
+
+			started := time.Now()
+			response, err := s.pingInstance(ctx, param.ServiceName, instance, param.Headers)
+			if err != nil {
+				log.Printf("❌ Failed to ping %s instance %s: %v", param.ServiceName, instance.ID, err)
+				response = &PingServiceResponse{
+					Service:   param.ServiceName,
+					Message:   fmt.Sprintf("Failed to ping %s: %v", param.ServiceName, err),
+					Instance:  instance,
+					Error:     err.Error(),
+					LatencyMs: latency.Milliseconds(time.Since(started)),
+					Stats:     s.instanceStats(instance),
+				}
+			}
+
+			results[i] = response
+		}(i)
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
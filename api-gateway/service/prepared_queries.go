@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"api-gateway/client/consul"
+)
+
+// PreparedQuery is a Consul prepared query definition, in Consul's own JSON format
+type PreparedQuery = consul.PreparedQuery
+
+var (
+	ErrPreparedQueryNotFound = consul.ErrPreparedQueryNotFound
+
+	ErrInvalidPreparedQuery = errors.New("invalid prepared query")
+)
+
+// validatePreparedQuery rejects definitions Consul could not execute
+func validatePreparedQuery(query *PreparedQuery) error {
+	if query == nil || strings.TrimSpace(query.Service.Service) == "" {
+		return fmt.Errorf("%w: service.service is required", ErrInvalidPreparedQuery)
+	}
+
+	return nil
+}
+
+// forgetPreparedQuery drops cached results of a changed query, so routes see the change
+// before its TTL expires. Results are cached by name or id, so everything is dropped.
+func (s *Service) forgetPreparedQuery() {
+	s.queries.Clear()
+}
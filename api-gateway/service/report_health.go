@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"api-gateway/client/consul"
+)
+
+// healthReportInterval is how often the gateway reports its health to Consul,
+// well within the TTL of its check
+const healthReportInterval = 10 * time.Second
+
+type ReportHealthParam struct {
+	Registration consul.Registration
+}
+
+// ReportHealth evaluates the gateway's health and reports it to its Consul TTL check
+// until ctx is done. If the gateway hangs or dies, the TTL expires and Consul marks it critical.
+// If Consul removed the registration meanwhile (e.g. after an outage kept the check
+// critical), the gateway registers again.
+func (s *Service) ReportHealth(ctx context.Context, param *ReportHealthParam) {
+	ticker := time.NewTicker(healthReportInterval)
+	defer ticker.Stop()
+
+	lastStatus := ""
+	for {
+		report := s.EvaluateHealth()
+
+		if report.Status != lastStatus {
+			log.Printf("🩺 Gateway health is %s: %s", report.Status, report.Output())
+			lastStatus = report.Status
+		}
+
+		if err := s.reportHealth(param.Registration, report); err != nil {
+			log.Printf("⚠️ Failed to report gateway health: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportHealth updates the TTL check, registering the gateway again when its check is gone
+func (s *Service) reportHealth(registration consul.Registration, report *HealthReport) error {
+	err := s.discoveryClient.UpdateTTL(registration.CheckID(), report.Status, report.Output())
+	if !errors.Is(err, consul.ErrCheckNotFound) {
+		return err
+	}
+
+	log.Printf("🔁 Gateway %s is not registered with Consul, registering again", registration.ID)
+
+	if err := s.discoveryClient.Register(registration); err != nil {
+		return err
+	}
+
+	return s.discoveryClient.UpdateTTL(registration.CheckID(), report.Status, report.Output())
+}
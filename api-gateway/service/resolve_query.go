@@ -0,0 +1,40 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/settings"
+)
+
+// cachedQuery is the result of a prepared query, reused until it expires
+type cachedQuery struct {
+	result  *consul.QueryResult
+	expires time.Time
+}
+
+// resolveQuery executes the prepared query of a route. The result is cached for
+// the query's DNS TTL, so queries without a TTL are executed on every request.
+func (s *Service) resolveQuery(route settings.Route) ([]consul.ServiceInstance, error) {
+	key := route.Datacenter + "/" + route.Query
+
+	if cached, ok := s.queries.Load(key); ok && time.Now().Before(cached.(*cachedQuery).expires) {
+		return cached.(*cachedQuery).result.Instances, nil
+	}
+
+	result, err := s.discoveryClient.ExecutePreparedQuery(route.Query, route.Datacenter)
+	if err != nil {
+		s.queries.Delete(key)
+		return nil, err
+	}
+
+	log.Printf("🧭 Prepared query %s resolved to %d instances of %s in %s (%d failovers)",
+		route.Query, len(result.Instances), result.Service, result.Datacenter, result.Failovers)
+
+	if result.TTL > 0 {
+		s.queries.Store(key, &cachedQuery{result: result, expires: time.Now().Add(result.TTL)})
+	}
+
+	return result.Instances, nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"log"
+
+	"api-gateway/util/apikey"
+)
+
+type RevokeAPIKeyParam struct {
+	KeyID string
+}
+
+// RevokeAPIKey revokes an API key. Every gateway stops accepting it
+// as soon as the change reaches its KV watch.
+func (s *Service) RevokeAPIKey(param *RevokeAPIKeyParam) (*apikey.Key, error) {
+	key, index, err := s.loadAPIKey(param.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.RevokedAt != nil {
+		return key.Public(), nil
+	}
+
+	key.Revoke()
+
+	if err := s.storeAPIKey(key, index); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🚫 Revoked API key %s of %s", key.ID, key.Owner)
+
+	return key.Public(), nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"log"
+)
+
+type RotateAPIKeyParam struct {
+	KeyID string
+}
+
+// RotateAPIKey replaces the secret of an API key; the previous key stops working
+func (s *Service) RotateAPIKey(param *RotateAPIKeyParam) (*APIKeyResponse, error) {
+	key, index, err := s.loadAPIKey(param.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+
+	token, err := key.Rotate()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storeAPIKey(key, index); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔄 Rotated API key %s of %s", key.ID, key.Owner)
+
+	return &APIKeyResponse{Key: key.Public(), Token: token}, nil
+}
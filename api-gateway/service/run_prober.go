@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/latency"
+	"api-gateway/util/uptime"
+)
+
+type RunProberParam struct {
+	Interval time.Duration
+}
+
+// RunProber probes every instance of every discovered service on an interval
+// and records the results in the uptime store until ctx is done
+func (s *Service) RunProber(ctx context.Context, param *RunProberParam) {
+	ticker := time.NewTicker(param.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.probeRound(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeRound probes all services once; the results of a round share its start time
+func (s *Service) probeRound(ctx context.Context) {
+	started := time.Now()
+
+	services, err := s.discoveryClient.GetAllServices()
+	if err != nil {
+		log.Printf("⚠️ Prober failed to discover services: %v", err)
+		return
+	}
+
+	var (
+		mu      sync.Mutex
+		results []uptime.Result
+		wg      sync.WaitGroup
+	)
+	for serviceName, tags := range services {
+		// Skip consul itself and the gateway replicas
+		if serviceName == "consul" || slices.Contains(tags, GatewayTag) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(serviceName string) {
+			defer wg.Done()
+
+			serviceResults := s.probeService(ctx, serviceName, started)
+
+			mu.Lock()
+			results = append(results, serviceResults...)
+			mu.Unlock()
+		}(serviceName)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err := s.uptime.Append(results...); err != nil {
+		log.Printf("⚠️ Failed to store probe results: %v", err)
+	}
+}
+
+// probeService pings every instance of a service that is not in maintenance
+// A service without instances to probe is recorded as down
+func (s *Service) probeService(ctx context.Context, serviceName string, round time.Time) []uptime.Result {
+	instances, err := s.discoveryClient.GetServiceHealth(serviceName)
+	if err != nil {
+		return []uptime.Result{{Time: round, Service: serviceName, Error: err.Error()}}
+	}
+
+	timeout := time.Duration(s.settings.Current().Route(serviceName).Timeout)
+
+	results := make([]uptime.Result, 0, len(instances))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := range instances {
+		if instances[i].Status == "maintenance" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(instance *consul.ServiceInstance) {
+			defer wg.Done()
+
+			result := s.probeInstance(ctx, instance, timeout)
+			result.Time = round
+
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(&instances[i].Instance)
+	}
+	wg.Wait()
+
+	if len(results) == 0 {
+		return []uptime.Result{{Time: round, Service: serviceName, Error: "no instances to probe"}}
+	}
+
+	return results
+}
+
+// probeInstance pings one instance
+// Probes are kept out of the upstream error rate, so a dead instance does not
+// degrade the gateway's own health
+func (s *Service) probeInstance(ctx context.Context, instance *consul.ServiceInstance, timeout time.Duration) uptime.Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s://%s:%d/ping", instance.Scheme(), instance.Address, instance.Port)
+
+	started := time.Now()
+	response, err := s.httpClient.Get(ctx, url, nil)
+	rtt := time.Since(started)
+
+	result := uptime.Result{
+		Service:   instance.Name,
+		Instance:  instance.ID,
+		LatencyMs: latency.Milliseconds(rtt),
+	}
+
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case response.StatusCode >= 500:
+		result.StatusCode = response.StatusCode
+		result.Error = fmt.Sprintf("status %d", response.StatusCode)
+	default:
+		result.StatusCode = response.StatusCode
+		result.Up = true
+	}
+
+	s.latency.Record(instance.Name, instance.ID, rtt, !result.Up)
+
+	return result
+}
@@ -1,18 +1,47 @@
 package service
 
 import (
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"api-gateway/client/consul"
 	"api-gateway/client/http_adapter"
+	"api-gateway/util/apikey"
+	"api-gateway/util/catalog"
+	"api-gateway/util/latency"
+	"api-gateway/util/settings"
+	"api-gateway/util/uptime"
 )
 
+// latencyWindow is how far back the rolling latency statistics reach
+const latencyWindow = 5 * time.Minute
+
 type Service struct {
 	httpClient      *http_adapter.Client
 	discoveryClient *consul.DiscoveryClient
+	settings        *settings.Store
+	apiKeys         *apikey.Store // nil when API keys are disabled
+	uptime          *uptime.Store // nil when the prober is disabled
+	catalog         *catalog.Hub
+
+	roundRobin sync.Map // service name -> *atomic.Uint64
+	queries    sync.Map // datacenter/prepared query -> *cachedQuery
+	loads      sync.Map // route discovery -> *routeLoads
+	rtts       atomic.Pointer[cachedRTTs]
+	upstream   upstreamStats
+	checks     checkHistory
+	latency    *latency.Tracker
 }
 
-func NewService(httpClient *http_adapter.Client, discoveryClient *consul.DiscoveryClient) *Service {
+func NewService(httpClient *http_adapter.Client, discoveryClient *consul.DiscoveryClient, settings *settings.Store, apiKeys *apikey.Store, uptime *uptime.Store, catalog *catalog.Hub) *Service {
 	return &Service{
 		httpClient:      httpClient,
 		discoveryClient: discoveryClient,
+		settings:        settings,
+		apiKeys:         apiKeys,
+		uptime:          uptime,
+		catalog:         catalog,
+		latency:         latency.NewTracker(latencyWindow),
 	}
 }
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/settings"
+)
+
+// smoothedLoad is the reported load of one instance, averaged over time
+type smoothedLoad struct {
+	value   float64
+	updated time.Time
+}
+
+// routeLoads are the smoothed loads of the instances one route discovers
+type routeLoads struct {
+	mu    sync.Mutex
+	loads map[string]*smoothedLoad // node/instance ID -> load
+}
+
+// routeLoadsFor returns the smoothed loads of a route's instances and forgets the instances
+// missing from instances, its latest discovery result. Routes discovering the same way
+// share their loads.
+func (s *Service) routeLoadsFor(route settings.Route, instances []consul.ServiceInstance) *routeLoads {
+	discovery := strings.Join([]string{route.Query, route.Service, route.Health, route.Datacenter, strings.Join(route.Failover, ",")}, "|")
+	entry, _ := s.loads.LoadOrStore(discovery, &routeLoads{loads: map[string]*smoothedLoad{}})
+	loads := entry.(*routeLoads)
+
+	current := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		current[instanceKey(instance)] = true
+	}
+
+	loads.mu.Lock()
+	defer loads.mu.Unlock()
+
+	for key := range loads.loads {
+		if !current[key] {
+			delete(loads.loads, key)
+		}
+	}
+
+	return loads
+}
+
+// smooth returns the exponentially weighted moving average of the load an instance
+// reports, with window as time constant. Traffic then shifts gradually instead of all
+// requests moving to whichever instance reported the lowest load last.
+func (l *routeLoads) smooth(instance consul.ServiceInstance, window time.Duration) float64 {
+	reported := *instance.Load
+	if window <= 0 {
+		return reported
+	}
+
+	now := time.Now()
+	key := instanceKey(instance)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	load, ok := l.loads[key]
+	if !ok {
+		l.loads[key] = &smoothedLoad{value: reported, updated: now}
+		return reported
+	}
+
+	// The weight of the new report grows with the time since the previous one,
+	// so the average does not depend on the request rate
+	alpha := 1 - math.Exp(-float64(now.Sub(load.updated))/float64(window))
+	load.value += alpha * (reported - load.value)
+	load.updated = now
+
+	return load.value
+}
+
+// instanceKey identifies an instance; IDs are only unique per node
+func instanceKey(instance consul.ServiceInstance) string {
+	return instance.Node + "/" + instance.ID
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"api-gateway/util/catalog"
+)
+
+type SubscribeCatalogEventsParam struct {
+	LastEventID *string // Resume after this event; nil for new events only
+}
+
+// CatalogEvents is a subscription to catalog changes
+type CatalogEvents struct {
+	Missed       []catalog.Event // Events published after LastEventID
+	Reset        bool            // LastEventID is unknown, events may have been missed
+	Subscription *catalog.Subscription
+}
+
+// SubscribeCatalogEvents subscribes to the changes of services, instances and their health,
+// optionally resuming after the last event a client received
+func (s *Service) SubscribeCatalogEvents(param *SubscribeCatalogEventsParam) *CatalogEvents {
+	if param.LastEventID == nil {
+		return &CatalogEvents{Subscription: s.catalog.Subscribe()}
+	}
+
+	missed, subscription, err := s.catalog.SubscribeAfter(*param.LastEventID)
+	if errors.Is(err, catalog.ErrUnknownEvent) {
+		log.Printf("⚠️ Cannot resume catalog events after %s, resetting the stream", *param.LastEventID)
+		return &CatalogEvents{Reset: true, Subscription: s.catalog.Subscribe()}
+	}
+
+	return &CatalogEvents{Missed: missed, Subscription: subscription}
+}
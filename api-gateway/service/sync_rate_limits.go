@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"api-gateway/util/ratelimit"
+)
+
+// GatewayTag marks the gateway replicas registered in Consul
+const GatewayTag = "gateway"
+
+// rateLimitSyncInterval is how often a replica publishes its rate limit usage
+const rateLimitSyncInterval = time.Second
+
+// replicaRefreshInterval is how often the gateway replicas are looked up in Consul
+const replicaRefreshInterval = 10 * time.Second
+
+// staleReportAfter is how long a report may go without updates before it is
+// removed, once its replica has also left the catalog
+const staleReportAfter = time.Minute
+
+type SyncRateLimitsParam struct {
+	Cluster     *ratelimit.Cluster
+	ServiceName string // Name the gateway replicas are registered under
+	KVPrefix    string
+}
+
+// SyncRateLimits shares the rate limit usage of this replica with the other
+// gateway replicas through Consul KV. Each replica writes only its own report
+// (<prefix>/<replica-id>) and watches the others. It returns when ctx is done.
+func (s *Service) SyncRateLimits(ctx context.Context, param *SyncRateLimitsParam) {
+	prefix := strings.TrimSuffix(param.KVPrefix, "/")
+	cluster := param.Cluster
+
+	go s.discoveryClient.WatchPrefix(ctx, prefix, func(index uint64, entries map[string][]byte) {
+		stale := cluster.Merge(entries, staleReportAfter)
+		if len(stale) == 0 {
+			return
+		}
+
+		// Keep the reports of replicas that are still registered, they may just be idle
+		replicas, err := s.gatewayReplicas(param.ServiceName)
+		if err != nil {
+			log.Printf("⚠️ Failed to look up gateway replicas: %v", err)
+			return
+		}
+
+		for _, replica := range stale {
+			if replicas[replica] {
+				continue
+			}
+
+			if err := s.discoveryClient.DeleteKey(prefix + "/" + replica); err != nil {
+				log.Printf("⚠️ Failed to remove rate limit report of %s: %v", replica, err)
+				continue
+			}
+			log.Printf("🧹 Removed rate limit report of departed replica %s", replica)
+		}
+	})
+
+	syncTicker := time.NewTicker(rateLimitSyncInterval)
+	defer syncTicker.Stop()
+
+	replicaTicker := time.NewTicker(replicaRefreshInterval)
+	defer replicaTicker.Stop()
+
+	replicaCount := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-syncTicker.C:
+			report, err := cluster.NextReport()
+			if err != nil || report == nil {
+				continue
+			}
+
+			if err := s.discoveryClient.PutKey(prefix+"/"+cluster.ReplicaID(), report); err != nil {
+				log.Printf("⚠️ Failed to publish rate limit usage: %v", err)
+			}
+
+		case <-replicaTicker.C:
+			replicas, err := s.gatewayReplicas(param.ServiceName)
+			if err != nil {
+				log.Printf("⚠️ Failed to look up gateway replicas: %v", err)
+				continue
+			}
+
+			if len(replicas) != replicaCount {
+				replicaCount = len(replicas)
+				log.Printf("👥 Sharing rate limits with %d gateway replicas", replicaCount)
+			}
+		}
+	}
+}
+
+// gatewayReplicas returns the ids of the healthy gateway replicas
+func (s *Service) gatewayReplicas(serviceName string) (map[string]bool, error) {
+	instances, err := s.discoveryClient.DiscoverService(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		replicas[instance.ID] = true
+	}
+
+	return replicas, nil
+}
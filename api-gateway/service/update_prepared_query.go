@@ -0,0 +1,30 @@
+package service
+
+import (
+	"log"
+)
+
+type UpdatePreparedQueryParam struct {
+	QueryID string
+	Query   *PreparedQuery
+}
+
+// UpdatePreparedQuery replaces the definition of an existing prepared query
+func (s *Service) UpdatePreparedQuery(param *UpdatePreparedQueryParam) (*PreparedQuery, error) {
+	if err := validatePreparedQuery(param.Query); err != nil {
+		return nil, err
+	}
+
+	query := *param.Query
+	query.ID = param.QueryID
+
+	if err := s.discoveryClient.UpdatePreparedQuery(&query); err != nil {
+		return nil, err
+	}
+
+	s.forgetPreparedQuery()
+
+	log.Printf("🧭 Updated prepared query %s (%s) for %s", query.ID, query.Name, query.Service.Service)
+
+	return &query, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// upstreamSlotWidth and upstreamSlots make up the rolling window
+// the upstream error rate is computed over (one minute)
+const (
+	upstreamSlotWidth = 10 * time.Second
+	upstreamSlots     = 6
+)
+
+// upstreamStats counts requests to services and the ones that failed
+type upstreamStats struct {
+	mu    sync.Mutex
+	slots [upstreamSlots]upstreamSlot
+}
+
+type upstreamSlot struct {
+	start    time.Time
+	requests int
+	errors   int
+}
+
+// record counts one request to a service
+func (u *upstreamStats) record(failed bool) {
+	now := time.Now()
+	start := now.Truncate(upstreamSlotWidth)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	slot := &u.slots[(start.Unix()/int64(upstreamSlotWidth.Seconds()))%upstreamSlots]
+	if !slot.start.Equal(start) {
+		*slot = upstreamSlot{start: start}
+	}
+
+	slot.requests++
+	if failed {
+		slot.errors++
+	}
+}
+
+// totals returns the requests and errors of the last minute
+func (u *upstreamStats) totals() (requests, errors int) {
+	oldest := time.Now().Add(-upstreamSlotWidth * upstreamSlots)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, slot := range u.slots {
+		if slot.start.After(oldest) {
+			requests += slot.requests
+			errors += slot.errors
+		}
+	}
+
+	return requests, errors
+}
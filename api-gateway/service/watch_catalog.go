@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"api-gateway/client/consul"
+	"api-gateway/util/catalog"
+)
+
+// WatchCatalog follows the registered instances and their health in Consul
+// and publishes every change to the catalog hub until ctx is done
+func (s *Service) WatchCatalog(ctx context.Context) {
+	log.Printf("👀 Watching the catalog and health checks in Consul")
+
+	s.discoveryClient.WatchHealth(ctx, func(instances []consul.InstanceHealth) {
+		s.checks.observe(instances, true)
+
+		state := make([]catalog.Instance, 0, len(instances))
+		for _, instance := range instances {
+			state = append(state, toCatalogInstance(instance))
+		}
+
+		for _, event := range s.catalog.Apply(state) {
+			switch event.Type {
+			case catalog.HealthChanged:
+				log.Printf("🚦 %s/%s is %s (was %s)", event.Service, event.Instance, event.Status, event.PreviousStatus)
+			default:
+				log.Printf("📋 Catalog %s: %s %s", event.Type, event.Service, event.Instance)
+			}
+		}
+	})
+}
+
+func toCatalogInstance(health consul.InstanceHealth) catalog.Instance {
+	// Explain why an instance is not passing
+	var outputs []string
+	for _, check := range health.Checks {
+		if check.Status != "passing" && check.Output != "" {
+			outputs = append(outputs, check.Output)
+		}
+	}
+
+	return catalog.Instance{
+		Service: health.Instance.Name,
+		ID:      health.Instance.ID,
+		Node:    health.Node,
+		Address: health.Instance.Address,
+		Port:    health.Instance.Port,
+		Tags:    health.Instance.Tags,
+		Meta:    health.Instance.Meta,
+		Status:  health.Status,
+		Output:  strings.Join(outputs, "; "),
+	}
+}
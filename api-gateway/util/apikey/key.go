@@ -0,0 +1,169 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// tokenPrefix marks gateway API keys so they are easy to spot in logs and secret scanners
+const tokenPrefix = "gwk"
+
+// AllServices allows a key to call every service
+const AllServices = "*"
+
+var (
+	ErrInvalidKey = errors.New("invalid API key")
+	ErrExpiredKey = errors.New("API key expired")
+	ErrRevokedKey = errors.New("API key revoked")
+)
+
+// Key is an API key record as stored in Consul KV under <prefix>/<id>.
+// Only the SHA-256 hash of the key is stored; the key itself is shown once on creation.
+type Key struct {
+	ID        string     `json:"id"`
+	Owner     string     `json:"owner"`
+	Services  []string   `json:"services"` // Consul services the key may call, "*" for all
+	Hash      string     `json:"hash,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// New creates a key record and returns it with the plain key
+func New(owner string, services []string, expiresAt *time.Time) (*Key, string, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &Key{
+		ID:        id,
+		Owner:     owner,
+		Services:  services,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: expiresAt,
+	}
+
+	token, err := key.newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, token, nil
+}
+
+// Rotate replaces the secret of the key; the previous key stops working
+func (k *Key) Rotate() (string, error) {
+	token, err := k.newSecret()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	k.RotatedAt = &now
+
+	return token, nil
+}
+
+// Revoke marks the key as revoked; the record is kept for auditing
+func (k *Key) Revoke() {
+	now := time.Now().UTC()
+	k.RevokedAt = &now
+}
+
+// Check reports whether the key can be used now
+func (k *Key) Check(now time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrRevokedKey
+	}
+
+	if k.ExpiresAt != nil && now.After(*k.ExpiresAt) {
+		return ErrExpiredKey
+	}
+
+	return nil
+}
+
+// Allows reports whether the key may call the given service
+func (k *Key) Allows(service string) bool {
+	return slices.Contains(k.Services, AllServices) || slices.Contains(k.Services, service)
+}
+
+// Public returns a copy of the key without its hash
+func (k *Key) Public() *Key {
+	public := *k
+	public.Hash = ""
+
+	return &public
+}
+
+// Validate checks a key record read from Consul KV
+func (k *Key) Validate() error {
+	if k.ID == "" || strings.ContainsAny(k.ID, "/_") {
+		return fmt.Errorf("invalid id %q", k.ID)
+	}
+
+	if strings.TrimSpace(k.Owner) == "" {
+		return errors.New("owner is required")
+	}
+
+	if len(k.Services) == 0 {
+		return errors.New("at least one service is required")
+	}
+
+	if len(k.Hash) != sha256.Size*2 {
+		return errors.New("invalid hash")
+	}
+
+	return nil
+}
+
+// matches compares the hash of token with the stored hash in constant time
+func (k *Key) matches(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash(token)), []byte(k.Hash)) == 1
+}
+
+func (k *Key) newSecret() (string, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	token := fmt.Sprintf("%s_%s_%s", tokenPrefix, k.ID, secret)
+	k.Hash = hash(token)
+
+	return token, nil
+}
+
+// parseID extracts the key id from a "gwk_<id>_<secret>" key
+func parseID(token string) (string, bool) {
+	parts := strings.Split(token, "_")
+	if len(parts) != 3 || parts[0] != tokenPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random key: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
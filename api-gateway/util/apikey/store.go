@@ -0,0 +1,108 @@
+package apikey
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Store holds the API keys read from the KV prefix and swaps them atomically
+type Store struct {
+	prefix string
+	keys   atomic.Pointer[map[string]*Key]
+}
+
+// NewStore creates an empty store for the keys under prefix
+func NewStore(prefix string) *Store {
+	store := &Store{prefix: strings.TrimSuffix(prefix, "/")}
+	store.keys.Store(&map[string]*Key{})
+
+	return store
+}
+
+// Prefix returns the KV prefix holding the keys
+func (s *Store) Prefix() string {
+	return s.prefix
+}
+
+// KVKey returns the KV key of the record with the given id
+func (s *Store) KVKey(id string) string {
+	return s.prefix + "/" + id
+}
+
+// Apply replaces the keys with the records read from the KV prefix.
+// The keys of entries are the key ids. Invalid records are skipped,
+// so a broken record can never grant access.
+func (s *Store) Apply(index uint64, entries map[string][]byte) {
+	keys := make(map[string]*Key, len(entries))
+
+	for id, value := range entries {
+		if len(value) == 0 {
+			continue
+		}
+
+		var key Key
+		if err := json.Unmarshal(value, &key); err != nil {
+			log.Printf("⚠️ Skipping API key %q: %v", id, err)
+			continue
+		}
+
+		if err := key.Validate(); err != nil || key.ID != id {
+			log.Printf("⚠️ Skipping invalid API key record %q", id)
+			continue
+		}
+
+		keys[id] = &key
+	}
+
+	s.keys.Store(&keys)
+	log.Printf("🔑 Loaded %d API keys (index %d)", len(keys), index)
+}
+
+// Authenticate returns the key record matching token
+func (s *Store) Authenticate(token string) (*Key, error) {
+	id, ok := parseID(token)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	key, ok := s.Get(id)
+	if !ok || !key.matches(token) {
+		return nil, ErrInvalidKey
+	}
+
+	if err := key.Check(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Get returns the key record with the given id
+func (s *Store) Get(id string) (*Key, bool) {
+	key, ok := (*s.keys.Load())[id]
+
+	return key, ok
+}
+
+// List returns every key record without hashes, ordered by owner and id
+func (s *Store) List() []*Key {
+	keys := *s.keys.Load()
+
+	list := make([]*Key, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key.Public())
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Owner != list[j].Owner {
+			return list[i].Owner < list[j].Owner
+		}
+		return list[i].ID < list[j].ID
+	})
+
+	return list
+}
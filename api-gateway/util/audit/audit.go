@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxRecentEntries is how many entries are kept in memory for the audit endpoint
+const maxRecentEntries = 200
+
+// Entry represents a single audited admin action
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	Target     string    `json:"target"`
+	Reason     string    `json:"reason,omitempty"`
+	RemoteAddr string    `json:"remote_addr"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Logger records admin actions to an append-only JSON lines file
+// and keeps the most recent entries in memory
+type Logger struct {
+	mu     sync.Mutex
+	file   *os.File
+	recent []Entry
+}
+
+// NewLogger creates an audit logger writing to the given path
+// An empty path only logs to stdout and memory
+func NewLogger(path string) (*Logger, error) {
+	logger := &Logger{}
+
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+		}
+
+		logger.file = file
+	}
+
+	return logger, nil
+}
+
+// Record stores an audit entry
+func (l *Logger) Record(entry Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	log.Printf("📝 AUDIT actor=%s action=%s target=%s success=%t reason=%q error=%q",
+		entry.Actor, entry.Action, entry.Target, entry.Success, entry.Reason, entry.Error)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.recent = append(l.recent, entry)
+	if len(l.recent) > maxRecentEntries {
+		l.recent = l.recent[len(l.recent)-maxRecentEntries:]
+	}
+
+	if l.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("❌ Failed to encode audit entry: %v", err)
+		return
+	}
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		log.Printf("❌ Failed to write audit entry: %v", err)
+	}
+}
+
+// Recent returns up to limit of the most recent entries, newest first
+func (l *Logger) Recent(limit int) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit <= 0 || limit > len(l.recent) {
+		limit = len(l.recent)
+	}
+
+	entries := make([]Entry, 0, limit)
+	for i := len(l.recent) - 1; i >= 0 && len(entries) < limit; i-- {
+		entries = append(entries, l.recent[i])
+	}
+
+	return entries
+}
+
+// Close closes the underlying audit file
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	return l.file.Close()
+}
@@ -0,0 +1,163 @@
+package catalog
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+)
+
+// EventType is the kind of change in the catalog
+type EventType string
+
+const (
+	ServiceAdded         EventType = "service_added"
+	ServiceRemoved       EventType = "service_removed"
+	InstanceRegistered   EventType = "instance_registered"
+	InstanceDeregistered EventType = "instance_deregistered"
+	HealthChanged        EventType = "health_changed"
+	InstanceUpdated      EventType = "instance_updated" // Tags, meta or address changed
+)
+
+// Reset is sent to a stream that cannot resume from its last event id;
+// the client should reload the catalog
+const Reset EventType = "reset"
+
+// EventTypes lists every catalog change
+var EventTypes = []EventType{ServiceAdded, ServiceRemoved, InstanceRegistered, InstanceDeregistered, HealthChanged, InstanceUpdated}
+
+// Instance is a registered service instance and its aggregated health
+type Instance struct {
+	Service string
+	ID      string
+	Node    string
+	Address string
+	Port    int
+	Tags    []string
+	Meta    map[string]string
+	Status  string // passing, warning, critical or maintenance
+	Output  string // Output of the checks that are not passing
+}
+
+// key identifies an instance; instance ids are only unique per node
+func (i Instance) key() string {
+	return i.Node + "/" + i.ID
+}
+
+// Event is one change in the catalog
+type Event struct {
+	ID             string            `json:"id,omitempty"` // <boot epoch>-<sequence>, unique across gateway restarts
+	Type           EventType         `json:"type"`
+	Time           time.Time         `json:"time"`
+	Service        string            `json:"service,omitempty"`
+	Instance       string            `json:"instance,omitempty"`
+	Node           string            `json:"node,omitempty"`
+	Address        string            `json:"address,omitempty"`
+	Port           int               `json:"port,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Meta           map[string]string `json:"meta,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	PreviousStatus string            `json:"previous_status,omitempty"`
+	Output         string            `json:"output,omitempty"`
+
+	seq uint64 // Sequence number of the event within its epoch
+}
+
+// Compare orders events by when they were published
+func (e Event) Compare(other Event) int {
+	return cmp.Compare(e.seq, other.seq)
+}
+
+// HasTag reports whether the instance of the event has a tag
+func (e Event) HasTag(tag string) bool {
+	return slices.Contains(e.Tags, tag)
+}
+
+func newEvent(eventType EventType, instance Instance) Event {
+	return Event{
+		Type:     eventType,
+		Service:  instance.Service,
+		Instance: instance.ID,
+		Node:     instance.Node,
+		Address:  instance.Address,
+		Port:     instance.Port,
+		Tags:     instance.Tags,
+		Meta:     instance.Meta,
+		Status:   instance.Status,
+		Output:   instance.Output,
+	}
+}
+
+// diff returns the events that turn the old instances into the new ones,
+// ordered by service: service additions first, then instance changes, then removals
+func diff(old, new map[string]Instance) []Event {
+	oldServices := services(old)
+	newServices := services(new)
+
+	var events []Event
+	for _, service := range sortedKeys(newServices) {
+		if _, ok := oldServices[service]; !ok {
+			events = append(events, Event{Type: ServiceAdded, Service: service, Tags: newServices[service]})
+		}
+	}
+
+	for _, key := range sortedKeys(new) {
+		instance := new[key]
+
+		previous, ok := old[key]
+		switch {
+		case !ok:
+			events = append(events, newEvent(InstanceRegistered, instance))
+
+		case previous.Status != instance.Status:
+			event := newEvent(HealthChanged, instance)
+			event.PreviousStatus = previous.Status
+			events = append(events, event)
+
+		case !slices.Equal(previous.Tags, instance.Tags) || !maps.Equal(previous.Meta, instance.Meta) ||
+			previous.Address != instance.Address || previous.Port != instance.Port:
+			events = append(events, newEvent(InstanceUpdated, instance))
+		}
+	}
+
+	for _, key := range sortedKeys(old) {
+		if _, ok := new[key]; !ok {
+			events = append(events, newEvent(InstanceDeregistered, old[key]))
+		}
+	}
+
+	for _, service := range sortedKeys(oldServices) {
+		if _, ok := newServices[service]; !ok {
+			events = append(events, Event{Type: ServiceRemoved, Service: service, Tags: oldServices[service]})
+		}
+	}
+
+	return events
+}
+
+// services returns the union of the instance tags of every service
+func services(instances map[string]Instance) map[string][]string {
+	services := make(map[string][]string)
+	for _, instance := range instances {
+		tags := services[instance.Service]
+		for _, tag := range instance.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if tags == nil {
+			tags = []string{}
+		}
+		services[instance.Service] = tags
+	}
+
+	for service := range services {
+		slices.Sort(services[service])
+	}
+
+	return services
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
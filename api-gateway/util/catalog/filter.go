@@ -0,0 +1,22 @@
+package catalog
+
+import "slices"
+
+// Filter selects events by service name and tag; empty lists match everything
+type Filter struct {
+	Services []string
+	Tags     []string // The event must carry at least one of them
+}
+
+// Match reports whether an event passes the filter
+func (f Filter) Match(event Event) bool {
+	if len(f.Services) > 0 && !slices.Contains(f.Services, event.Service) {
+		return false
+	}
+
+	if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, event.HasTag) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,227 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is dropped
+const subscriberBuffer = 256
+
+// historySize is how many past events are kept for subscribers that resume
+const historySize = 1000
+
+// ErrUnknownEvent is returned when resuming after an event that is no longer kept,
+// or that this gateway process never published (e.g. it restarted since)
+var ErrUnknownEvent = errors.New("unknown event id")
+
+// Hub keeps the last known state of the catalog and fans out its changes to subscribers
+type Hub struct {
+	mu          sync.Mutex
+	synced      bool
+	instances   map[string]Instance // node/id -> instance
+	epoch       string              // Start time of this hub, prefixes its event ids
+	lastSeq     uint64
+	history     []Event // The last historySize events, oldest first
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// Subscription receives catalog events until it is closed.
+// C is closed when the subscription is closed, also when the subscriber fell too
+// far behind; Dropped then reports true.
+type Subscription struct {
+	C     <-chan Event
+	After string // The subscription receives the events after this id
+
+	hub     *Hub
+	events  chan Event
+	dropped bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		instances:   make(map[string]Instance),
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Apply replaces the known state with instances and publishes the changes.
+// The first call only records the state, so the gateway does not report every
+// instance as new when it starts.
+func (h *Hub) Apply(instances []Instance) []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state := make(map[string]Instance, len(instances))
+	for _, instance := range instances {
+		state[instance.key()] = instance
+	}
+
+	if !h.synced {
+		h.instances = state
+		h.synced = true
+		return nil
+	}
+
+	events := diff(h.instances, state)
+	h.instances = state
+
+	now := time.Now()
+	for i := range events {
+		h.lastSeq++
+		events[i].seq = h.lastSeq
+		events[i].ID = h.eventID(h.lastSeq)
+		events[i].Time = now
+
+		h.history = append(h.history, events[i])
+		h.publish(events[i])
+	}
+
+	if len(h.history) > historySize {
+		h.history = append([]Event(nil), h.history[len(h.history)-historySize:]...)
+	}
+
+	return events
+}
+
+// Synced reports whether the catalog state was read at least once
+func (h *Hub) Synced() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.synced
+}
+
+// Instances returns the last known state of the catalog; ok is false until it was read
+func (h *Hub) Instances() (instances []Instance, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return slices.Collect(maps.Values(h.instances)), h.synced
+}
+
+// Subscribe returns a subscription to the events published from now on
+func (h *Hub) Subscribe() *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.subscribe()
+}
+
+// SubscribeAfter returns the kept events published after lastID together with
+// a subscription to the following ones, so a subscriber can resume without gaps.
+// It returns ErrUnknownEvent if lastID is from another epoch, e.g. before the gateway
+// restarted, or if events after it were already discarded.
+func (h *Hub) SubscribeAfter(lastID string) ([]Event, *Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	lastSeq, err := h.sequence(lastID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The first kept event must directly follow lastID
+	if lastSeq < h.lastSeq && (len(h.history) == 0 || h.history[0].seq > lastSeq+1) {
+		return nil, nil, ErrUnknownEvent
+	}
+
+	var missed []Event
+	for _, event := range h.history {
+		if event.seq > lastSeq {
+			missed = append(missed, event)
+		}
+	}
+
+	return missed, h.subscribe(), nil
+}
+
+// Close closes every subscription, e.g. to end open streams on shutdown
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for subscription := range h.subscribers {
+		h.remove(subscription)
+	}
+}
+
+func (h *Hub) subscribe() *Subscription {
+	events := make(chan Event, subscriberBuffer)
+	subscription := &Subscription{C: events, After: h.eventID(h.lastSeq), hub: h, events: events}
+
+	if h.closed {
+		close(events)
+		return subscription
+	}
+
+	h.subscribers[subscription] = struct{}{}
+
+	return subscription
+}
+
+// eventID formats the id of the event with sequence number seq
+func (h *Hub) eventID(seq uint64) string {
+	return fmt.Sprintf("%s-%d", h.epoch, seq)
+}
+
+// sequence returns the sequence number of an event id this hub published
+func (h *Hub) sequence(id string) (uint64, error) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != h.epoch {
+		return 0, ErrUnknownEvent
+	}
+
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || n > h.lastSeq {
+		return 0, ErrUnknownEvent
+	}
+
+	return n, nil
+}
+
+// Close stops the subscription and closes C
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
+
+// Dropped reports whether the subscription was closed because it fell behind
+func (s *Subscription) Dropped() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.dropped
+}
+
+// publish sends an event to every subscriber, dropping the ones that are full
+func (h *Hub) publish(event Event) {
+	for subscription := range h.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			subscription.dropped = true
+			h.remove(subscription)
+		}
+	}
+}
+
+func (h *Hub) remove(subscription *Subscription) {
+	if _, ok := h.subscribers[subscription]; !ok {
+		return
+	}
+
+	delete(h.subscribers, subscription)
+	close(subscription.events)
+}
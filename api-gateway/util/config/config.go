@@ -1,50 +1,240 @@
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"log"
 	"os"
+	"reflect"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
+// redactedValue replaces secrets when the configuration is printed
+const redactedValue = "**redacted**"
+
 // Config holds all configuration for the application
 type Config struct {
-	App    App    `mapstructure:"app"`
-	Consul Consul `mapstructure:"consul"`
+	App      App      `mapstructure:"app" json:"app"`
+	Consul   Consul   `mapstructure:"consul" json:"consul"`
+	Admin    Admin    `mapstructure:"admin" json:"admin"`
+	Upstream Upstream `mapstructure:"upstream" json:"upstream"`
+
+	DynamicConfig DynamicConfig `mapstructure:"dynamic_config" json:"dynamic_config"`
+	JWT           JWT           `mapstructure:"jwt" json:"jwt"`
+	APIKeys       APIKeys       `mapstructure:"api_keys" json:"api_keys"`
+	Cluster       Cluster       `mapstructure:"cluster" json:"cluster"`
+	Prober        Prober        `mapstructure:"prober" json:"prober"`
+	Webhooks      Webhooks      `mapstructure:"webhooks" json:"webhooks"`
+}
+
+// defaults are applied before any config file, environment variable or flag
+var defaults = map[string]interface{}{
+	"app.name":      "api-gateway",
+	"app.host":      "0.0.0.0",
+	"app.port":      4000,
+	"consul.host":   "localhost",
+	"consul.port":   8500,
+	"consul.scheme": "http",
+
+	"dynamic_config.kv_prefix": "api-gateway/config",
+	"api_keys.kv_prefix":       "api-gateway/api-keys",
+	"cluster.kv_prefix":        "api-gateway/rate-limits",
+
+	"prober.interval":   "30s",
+	"prober.store_path": "uptime.jsonl",
+	"prober.retention":  "168h",
+
+	"webhooks.cooldown":      "1m",
+	"webhooks.retries":       3,
+	"webhooks.retry_backoff": "1s",
+	"webhooks.timeout":       "5s",
+}
+
+// Flags holds the command line overrides for the configuration
+type Flags struct {
+	// Path is a config file or a directory containing config.{json,yaml,yml,toml}
+	Path string
+
+	values map[string]*string
 }
 
-// LoadConfig reads configuration from file or environment variables.
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("config")
-	viper.SetConfigType("json")
+// RegisterFlags registers --config plus one flag per configuration key on fs.
+// Flag names are derived from the keys: "app.port" becomes --port,
+// "consul.host" becomes --consul-host.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{values: make(map[string]*string)}
+
+	fs.StringVar(&flags.Path, "config", "", "config file, or directory containing config.{json,yaml,yml,toml} (default \".\")")
+
+	for _, key := range configKeys() {
+		flags.values[key] = fs.String(FlagName(key), "", fmt.Sprintf("overrides %s (env %s)", key, EnvName(key)))
+	}
+
+	return flags
+}
 
-	// Enable automatic environment variable reading
-	viper.AutomaticEnv()
+// FlagName returns the command line flag name of a configuration key
+func FlagName(key string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(strings.TrimPrefix(key, "app."))
+}
 
-	// Set up environment variable mappings for nested config
-	viper.BindEnv("consul.host", "CONSUL_HOST")
-	viper.BindEnv("consul.port", "CONSUL_PORT")
-	viper.BindEnv("consul.scheme", "CONSUL_SCHEME")
+// envPrefix starts the environment variable names of this binary,
+// so they do not collide with the variables of other tools
+const envPrefix = "GATEWAY_"
 
-	err = viper.ReadInConfig()
+// EnvName returns the environment variable name of a configuration key:
+// "consul.host" becomes GATEWAY_CONSUL_HOST
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// LoadConfig reads and validates the configuration.
+//
+// Values are resolved with the following precedence (highest first):
+//  1. command line flags (--port, --consul-host, ...)
+//  2. environment variables (GATEWAY_APP_PORT, GATEWAY_CONSUL_HOST, ...)
+//  3. the config file (config.json, config.yaml, config.yml or config.toml)
+//  4. built-in defaults
+//
+// The config file is optional unless it is explicitly set with --config,
+// so a container can be configured with environment variables alone.
+func LoadConfig(flags *Flags) (config Config, err error) {
+	config, err = ReadConfig(flags)
 	if err != nil {
-		return config, fmt.Errorf("failed to read configuration file: %s", err)
+		return config, err
 	}
 
-	err = viper.Unmarshal(&config)
+	err = config.Validate()
 	if err != nil {
-		return config, fmt.Errorf("failed to unmarshal configuration: %s", err)
+		return config, err
+	}
+
+	log.Printf("🔧 Consul configuration: %s://%s:%d", config.Consul.Scheme, config.Consul.Host, config.Consul.Port)
+
+	return
+}
+
+// ReadConfig reads the effective configuration without validating it
+func ReadConfig(flags *Flags) (config Config, err error) {
+	if flags == nil {
+		flags = &Flags{}
+	}
+
+	v := viper.New()
+
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	// Every configuration key can be set from the environment
+	for _, key := range configKeys() {
+		v.BindEnv(key, EnvName(key))
+	}
+
+	// Only flags that were actually given override the other sources
+	for key, value := range flags.values {
+		if *value != "" {
+			v.Set(key, *value)
+		}
+	}
+
+	explicitFile := false
+	if info, statErr := os.Stat(flags.Path); statErr == nil && !info.IsDir() {
+		// The file format is taken from the extension
+		v.SetConfigFile(flags.Path)
+		explicitFile = true
+	} else {
+		path := flags.Path
+		if path == "" {
+			path = "."
+		}
+
+		v.AddConfigPath(path)
+		v.SetConfigName("config")
+		explicitFile = flags.Path != ""
 	}
 
-	// Log the final Consul configuration for debugging
-	consulHost := config.Consul.Host
-	if envHost := os.Getenv("CONSUL_HOST"); envHost != "" {
-		consulHost = envHost
-		config.Consul.Host = envHost
+	err = v.ReadInConfig()
+	if err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if explicitFile || !errors.As(err, &notFound) {
+			return config, fmt.Errorf("failed to read configuration file: %s", err)
+		}
+
+		log.Printf("🔧 No config file found, using environment variables and defaults")
 	}
 
-	fmt.Printf("🔧 Consul configuration: %s://%s:%d\n", config.Consul.Scheme, consulHost, config.Consul.Port)
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return config, fmt.Errorf("failed to unmarshal configuration: %s", err)
+	}
 
 	return
 }
+
+// Redacted returns a copy of the configuration that is safe to print
+func (c Config) Redacted() Config {
+	redacted := c
+
+	if c.Consul.Token != "" {
+		redacted.Consul.Token = redactedValue
+	}
+
+	if c.JWT.Secret != "" {
+		redacted.JWT.Secret = redactedValue
+	}
+
+	// Webhook headers usually carry credentials
+	redacted.Webhooks.Targets = make([]WebhookTarget, len(c.Webhooks.Targets))
+	for i, target := range c.Webhooks.Targets {
+		headers := make(map[string]string, len(target.Headers))
+		for name := range target.Headers {
+			headers[name] = redactedValue
+		}
+		target.Headers = headers
+		redacted.Webhooks.Targets[i] = target
+	}
+
+	redacted.Admin.Tokens = make([]string, len(c.Admin.Tokens))
+	for i := range c.Admin.Tokens {
+		redacted.Admin.Tokens[i] = redactedValue
+	}
+
+	return redacted
+}
+
+// configKeys returns every leaf configuration key, e.g. "app.port"
+func configKeys() []string {
+	return collectKeys(reflect.TypeOf(Config{}), "")
+}
+
+func collectKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		// Lists of objects can only be set in the config file
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			continue
+		}
+
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, collectKeys(field.Type, key+".")...)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
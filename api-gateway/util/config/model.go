@@ -1,16 +1,137 @@
 package config
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // App config
 
 type App struct {
-	Name string `mapstructure:"name"`
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Name string `mapstructure:"name" json:"name"`
+	Host string `mapstructure:"host" json:"host"`
+	Port int    `mapstructure:"port" json:"port"`
+
+	RegisterAddress string `mapstructure:"register_address" json:"register_address"` // Address other replicas reach this gateway at (default: hostname)
 }
 
 // Consul config
 type Consul struct {
-	Host   string `mapstructure:"host"`
-	Port   int    `mapstructure:"port"`
-	Scheme string `mapstructure:"scheme"`
+	Host   string `mapstructure:"host" json:"host"`
+	Port   int    `mapstructure:"port" json:"port"`
+	Scheme string `mapstructure:"scheme" json:"scheme"`
+
+	// ACL token sent with every Consul request, given inline or read from token_file
+	Token     string `mapstructure:"token" json:"token"`
+	TokenFile string `mapstructure:"token_file" json:"token_file"`
+
+	// TLS settings for talking to a Consul HTTPS listener (requires scheme "https")
+	CAFile             string `mapstructure:"ca_file" json:"ca_file"`                           // CA bundle used to verify the Consul server
+	CertFile           string `mapstructure:"cert_file" json:"cert_file"`                       // Client certificate presented to Consul
+	KeyFile            string `mapstructure:"key_file" json:"key_file"`                         // Private key of the client certificate
+	TLSServerName      string `mapstructure:"tls_server_name" json:"tls_server_name"`           // Server name expected in the Consul certificate
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify" json:"insecure_skip_verify"` // Disable Consul certificate verification (development only)
+}
+
+// Admin config
+
+type Admin struct {
+	Tokens       []string `mapstructure:"tokens" json:"tokens"`                 // Bearer tokens accepted by the /admin endpoints
+	AuditLogPath string   `mapstructure:"audit_log_path" json:"audit_log_path"` // File where admin actions are appended (empty = stdout log only)
+}
+
+// Upstream config
+
+type Upstream struct {
+	// TLS settings used when calling services that advertise protocol "https"
+	CAFile             string `mapstructure:"ca_file" json:"ca_file"`                           // CA bundle used to verify services (default: system roots)
+	CertFile           string `mapstructure:"cert_file" json:"cert_file"`                       // Client certificate presented to services (mTLS)
+	KeyFile            string `mapstructure:"key_file" json:"key_file"`                         // Private key of the client certificate
+	ServerName         string `mapstructure:"server_name" json:"server_name"`                   // Expected name in service certificates (default: instance address)
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify" json:"insecure_skip_verify"` // Disable service certificate verification (development only)
+}
+
+// DynamicConfig config
+
+type DynamicConfig struct {
+	Enabled  bool   `mapstructure:"enabled" json:"enabled"`     // Watch Consul KV for runtime settings (routes, timeouts, rate limits, balancer)
+	KVPrefix string `mapstructure:"kv_prefix" json:"kv_prefix"` // KV prefix holding the settings
+}
+
+// JWT config
+
+type JWT struct {
+	Enabled       bool   `mapstructure:"enabled" json:"enabled"`                 // Require a valid JWT on /api/* routes
+	Secret        string `mapstructure:"secret" json:"secret"`                   // HS256 shared secret
+	SecretFile    string `mapstructure:"secret_file" json:"secret_file"`         // File containing the HS256 shared secret
+	PublicKeyFile string `mapstructure:"public_key_file" json:"public_key_file"` // PEM RSA/ECDSA public key for RS256/ES256
+	JWKSURL       string `mapstructure:"jwks_url" json:"jwks_url"`               // JWKS endpoint for RS256/ES256 keys
+	Issuer        string `mapstructure:"issuer" json:"issuer"`                   // Expected "iss" claim (optional)
+	Audience      string `mapstructure:"audience" json:"audience"`               // Expected "aud" claim (optional)
+}
+
+// APIKeys config
+
+type APIKeys struct {
+	Enabled  bool   `mapstructure:"enabled" json:"enabled"`     // Accept API keys (X-API-Key) on /api/* routes
+	KVPrefix string `mapstructure:"kv_prefix" json:"kv_prefix"` // KV prefix holding the hashed keys
+}
+
+// Cluster config
+
+type Cluster struct {
+	Enabled  bool   `mapstructure:"enabled" json:"enabled"`     // Register in Consul and share rate limit usage with the other replicas
+	KVPrefix string `mapstructure:"kv_prefix" json:"kv_prefix"` // KV prefix holding the usage reports of the replicas
+}
+
+// Prober config
+
+type Prober struct {
+	Enabled   bool          `mapstructure:"enabled" json:"enabled"`       // Probe every service instance in the background
+	Interval  time.Duration `mapstructure:"interval" json:"interval"`     // Time between probe rounds, e.g. "30s"
+	StorePath string        `mapstructure:"store_path" json:"store_path"` // JSON lines file the probe results are kept in
+	Retention time.Duration `mapstructure:"retention" json:"retention"`   // How long probe results are kept, e.g. "168h"
+}
+
+// MarshalJSON writes the durations as "30s" instead of nanoseconds
+func (p Prober) MarshalJSON() ([]byte, error) {
+	type prober Prober
+
+	return json.Marshal(struct {
+		prober
+		Interval  string `json:"interval"`
+		Retention string `json:"retention"`
+	}{prober(p), p.Interval.String(), p.Retention.String()})
+}
+
+// Webhooks config
+
+type Webhooks struct {
+	Enabled      bool            `mapstructure:"enabled" json:"enabled"`             // POST catalog and health changes to the targets
+	Targets      []WebhookTarget `mapstructure:"targets" json:"targets"`             // Only settable in the config file
+	Cooldown     time.Duration   `mapstructure:"cooldown" json:"cooldown"`           // Minimum time between notifications about one service, e.g. "1m"
+	Retries      int             `mapstructure:"retries" json:"retries"`             // Attempts after a failed delivery
+	RetryBackoff time.Duration   `mapstructure:"retry_backoff" json:"retry_backoff"` // Wait before the first retry, doubled on every retry
+	Timeout      time.Duration   `mapstructure:"timeout" json:"timeout"`             // Timeout of one delivery attempt
+}
+
+// WebhookTarget is a URL notified about catalog and health changes
+type WebhookTarget struct {
+	URL      string            `mapstructure:"url" json:"url"`
+	Events   []string          `mapstructure:"events" json:"events"`     // Event types to send, all when empty
+	Services []string          `mapstructure:"services" json:"services"` // Services to send events of, all when empty
+	Headers  map[string]string `mapstructure:"headers" json:"headers"`   // Extra request headers, e.g. Authorization
+	Template string            `mapstructure:"template" json:"template"` // Go template of the JSON payload, the event itself when empty
+}
+
+// MarshalJSON writes the durations as "1m0s" instead of nanoseconds
+func (w Webhooks) MarshalJSON() ([]byte, error) {
+	type webhooks Webhooks
+
+	return json.Marshal(struct {
+		webhooks
+		Cooldown     string `json:"cooldown"`
+		RetryBackoff string `json:"retry_backoff"`
+		Timeout      string `json:"timeout"`
+	}{webhooks(w), w.Cooldown.String(), w.RetryBackoff.String(), w.Timeout.String()})
 }
@@ -0,0 +1,300 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// hostnamePattern matches RFC 1123 host names such as "consul" or "service-a.internal"
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// FieldError describes why a single configuration field is invalid
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists every configuration field that failed validation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field.Field, field.Message))
+	}
+
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(messages, "; "))
+}
+
+// validator collects field errors while checking a configuration
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) fail(field, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.fail(field, "is required")
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) port(field string, value int) {
+	if value < 1 || value > 65535 {
+		v.fail(field, "must be between 1 and 65535, got %d", value)
+	}
+}
+
+func (v *validator) host(field, value string) {
+	if net.ParseIP(value) == nil && !hostnamePattern.MatchString(value) {
+		v.fail(field, "must be an IP address or host name, got %q", value)
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+
+	v.fail(field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// file checks that an optional file setting points to a readable file
+func (v *validator) file(field, path string) {
+	if path == "" {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		v.fail(field, "cannot read %q: %v", path, err)
+		return
+	}
+
+	if info.IsDir() {
+		v.fail(field, "must be a file, %q is a directory", path)
+	}
+}
+
+// Validate checks the configuration and reports every invalid field at once
+func (c Config) Validate() error {
+	v := &validator{}
+
+	c.App.validate(v)
+	c.Consul.validate(v)
+	c.Admin.validate(v)
+	c.Upstream.validate(v)
+	c.DynamicConfig.validate(v)
+	c.JWT.validate(v)
+	c.APIKeys.validate(v)
+	c.Cluster.validate(v)
+	c.Prober.validate(v)
+	c.Webhooks.validate(v)
+	c.validatePrefixes(v)
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Fields: v.errors}
+	}
+
+	return nil
+}
+
+func (a App) validate(v *validator) {
+	v.required("app.name", a.Name)
+	if v.required("app.host", a.Host) {
+		v.host("app.host", a.Host)
+	}
+	v.port("app.port", a.Port)
+
+	if a.RegisterAddress != "" {
+		v.host("app.register_address", a.RegisterAddress)
+	}
+}
+
+func (c Consul) validate(v *validator) {
+	if v.required("consul.host", c.Host) {
+		v.host("consul.host", c.Host)
+	}
+	v.port("consul.port", c.Port)
+	v.oneOf("consul.scheme", c.Scheme, "http", "https")
+
+	if c.Token != "" && c.TokenFile != "" {
+		v.fail("consul.token_file", "must not be set together with consul.token")
+	}
+	v.file("consul.token_file", c.TokenFile)
+
+	v.file("consul.ca_file", c.CAFile)
+	v.file("consul.cert_file", c.CertFile)
+	v.file("consul.key_file", c.KeyFile)
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		v.fail("consul.cert_file", "must be set together with consul.key_file")
+	}
+
+	usesTLS := c.CAFile != "" || c.CertFile != "" || c.TLSServerName != "" || c.InsecureSkipVerify
+	if usesTLS && c.Scheme != "https" {
+		v.fail("consul.scheme", "must be \"https\" when TLS settings are configured, got %q", c.Scheme)
+	}
+}
+
+func (a Admin) validate(v *validator) {
+	for i, token := range a.Tokens {
+		if strings.TrimSpace(token) == "" {
+			v.fail(fmt.Sprintf("admin.tokens[%d]", i), "must not be empty")
+		}
+	}
+}
+
+func (u Upstream) validate(v *validator) {
+	v.file("upstream.ca_file", u.CAFile)
+	v.file("upstream.cert_file", u.CertFile)
+	v.file("upstream.key_file", u.KeyFile)
+	if (u.CertFile == "") != (u.KeyFile == "") {
+		v.fail("upstream.cert_file", "must be set together with upstream.key_file")
+	}
+}
+
+func (d DynamicConfig) validate(v *validator) {
+	if d.Enabled {
+		v.required("dynamic_config.kv_prefix", d.KVPrefix)
+	}
+}
+
+func (j JWT) validate(v *validator) {
+	if !j.Enabled {
+		return
+	}
+
+	if j.Secret == "" && j.SecretFile == "" && j.PublicKeyFile == "" && j.JWKSURL == "" {
+		v.fail("jwt", "one of secret, secret_file, public_key_file or jwks_url is required when enabled")
+	}
+
+	if j.Secret != "" && j.SecretFile != "" {
+		v.fail("jwt.secret_file", "must not be set together with jwt.secret")
+	}
+
+	v.file("jwt.secret_file", j.SecretFile)
+	v.file("jwt.public_key_file", j.PublicKeyFile)
+
+	if j.JWKSURL != "" {
+		if u, err := url.Parse(j.JWKSURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.fail("jwt.jwks_url", "must be an http(s) URL, got %q", j.JWKSURL)
+		}
+	}
+}
+
+func (a APIKeys) validate(v *validator) {
+	if a.Enabled {
+		v.required("api_keys.kv_prefix", a.KVPrefix)
+	}
+}
+
+func (c Cluster) validate(v *validator) {
+	if c.Enabled {
+		v.required("cluster.kv_prefix", c.KVPrefix)
+	}
+}
+
+func (p Prober) validate(v *validator) {
+	if !p.Enabled {
+		return
+	}
+
+	if p.Interval < time.Second {
+		v.fail("prober.interval", "must be at least 1s, got %s", p.Interval)
+	}
+
+	if p.Retention < p.Interval {
+		v.fail("prober.retention", "must be at least prober.interval, got %s", p.Retention)
+	}
+}
+
+// webhookEvents are the catalog event types a webhook target can select
+var webhookEvents = []string{
+	"service_added", "service_removed",
+	"instance_registered", "instance_deregistered",
+	"health_changed", "instance_updated",
+}
+
+func (w Webhooks) validate(v *validator) {
+	if !w.Enabled {
+		return
+	}
+
+	if len(w.Targets) == 0 {
+		v.fail("webhooks.targets", "at least one target is required when enabled")
+	}
+
+	for i, target := range w.Targets {
+		field := fmt.Sprintf("webhooks.targets[%d]", i)
+
+		if u, err := url.Parse(target.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.fail(field+".url", "must be an http(s) URL, got %q", target.URL)
+		}
+
+		for _, event := range target.Events {
+			v.oneOf(field+".events", event, webhookEvents...)
+		}
+	}
+
+	if w.Cooldown < 0 {
+		v.fail("webhooks.cooldown", "must not be negative, got %s", w.Cooldown)
+	}
+
+	if w.Retries < 0 {
+		v.fail("webhooks.retries", "must not be negative, got %d", w.Retries)
+	}
+
+	if w.RetryBackoff <= 0 {
+		v.fail("webhooks.retry_backoff", "must be positive, got %s", w.RetryBackoff)
+	}
+
+	if w.Timeout <= 0 {
+		v.fail("webhooks.timeout", "must be positive, got %s", w.Timeout)
+	}
+}
+
+// validatePrefixes checks that the enabled features do not read each other's KV keys
+func (c Config) validatePrefixes(v *validator) {
+	type prefix struct{ field, value string }
+
+	var prefixes []prefix
+	if c.DynamicConfig.Enabled {
+		prefixes = append(prefixes, prefix{"dynamic_config.kv_prefix", c.DynamicConfig.KVPrefix})
+	}
+	if c.APIKeys.Enabled {
+		prefixes = append(prefixes, prefix{"api_keys.kv_prefix", c.APIKeys.KVPrefix})
+	}
+	if c.Cluster.Enabled {
+		prefixes = append(prefixes, prefix{"cluster.kv_prefix", c.Cluster.KVPrefix})
+	}
+
+	for i := range prefixes {
+		for _, other := range prefixes[:i] {
+			if prefixesOverlap(prefixes[i].value, other.value) {
+				v.fail(prefixes[i].field, "must not overlap %s %q", other.field, other.value)
+			}
+		}
+	}
+}
+
+// prefixesOverlap reports whether one KV prefix contains the other
+func prefixesOverlap(a, b string) bool {
+	a = strings.TrimSuffix(a, "/") + "/"
+	b = strings.TrimSuffix(b, "/") + "/"
+
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
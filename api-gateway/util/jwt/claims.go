@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims are the verified claims of a token
+type Claims map[string]interface{}
+
+// String returns a string claim, or "" when it is missing or not a string
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Subject returns the "sub" claim
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Issuer returns the "iss" claim
+func (c Claims) Issuer() string {
+	return c.String("iss")
+}
+
+// Scopes returns the token scopes, read from the space separated "scope"
+// claim (RFC 8693) or the "scp" array used by some identity providers
+func (c Claims) Scopes() []string {
+	if scope := c.String("scope"); scope != "" {
+		return strings.Fields(scope)
+	}
+
+	return c.strings("scp")
+}
+
+// Audience returns the "aud" claim, which may be a string or an array
+func (c Claims) Audience() []string {
+	if aud := c.String("aud"); aud != "" {
+		return []string{aud}
+	}
+
+	return c.strings("aud")
+}
+
+// HasScope reports whether the token carries a scope
+func (c Claims) HasScope(scope string) bool {
+	for _, candidate := range c.Scopes() {
+		if candidate == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches reports whether a claim equals the expected value
+// Array claims match when one of their elements equals the value
+func (c Claims) Matches(name, expected string) bool {
+	switch value := c[name].(type) {
+	case string:
+		return value == expected
+	case []interface{}:
+		for _, element := range value {
+			if fmt.Sprint(element) == expected {
+				return true
+			}
+		}
+	case nil:
+		return false
+	default:
+		return fmt.Sprint(value) == expected
+	}
+
+	return false
+}
+
+func (c Claims) strings(name string) []string {
+	values, _ := c[name].([]interface{})
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// time returns a NumericDate claim (seconds since epoch)
+func (c Claims) time(name string) (time.Time, bool) {
+	seconds, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(seconds), 0), true
+}
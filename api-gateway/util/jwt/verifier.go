@@ -0,0 +1,210 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is the tolerance applied to exp and nbf
+const clockSkew = 30 * time.Second
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+// ErrInvalidToken is returned (wrapped) for every token that fails verification
+var ErrInvalidToken = errors.New("invalid token")
+
+// Options configures a Verifier
+type Options struct {
+	Secret    []byte           // HS256 shared secret
+	PublicKey crypto.PublicKey // Static RS256/ES256 key
+	JWKS      *JWKS            // Keys fetched from a JWKS URL
+
+	Issuer   string // Expected "iss", empty to skip the check
+	Audience string // Expected "aud" entry, empty to skip the check
+}
+
+// Verifier verifies JWT signatures and standard claims
+type Verifier struct {
+	options Options
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// NewVerifier creates a verifier; at least one key source is required
+func NewVerifier(options Options) (*Verifier, error) {
+	if len(options.Secret) == 0 && options.PublicKey == nil && options.JWKS == nil {
+		return nil, errors.New("no JWT key configured: set a secret, a public key file or a JWKS URL")
+	}
+
+	return &Verifier{options: options}, nil
+}
+
+// Verify checks the token signature, expiry, issuer and audience
+// and returns its claims
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: bad header: %v", ErrInvalidToken, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature encoding", ErrInvalidToken)
+	}
+
+	if err := v.verifySignature(h, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: bad claims: %v", ErrInvalidToken, err)
+	}
+
+	if err := v.verifyClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) verifySignature(h header, signed, signature []byte) error {
+	digest := sha256.Sum256(signed)
+
+	switch h.Alg {
+	case AlgHS256:
+		if len(v.options.Secret) == 0 {
+			return errors.New("HS256 tokens are not accepted")
+		}
+
+		mac := hmac.New(sha256.New, v.options.Secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("signature mismatch")
+		}
+
+		return nil
+
+	case AlgRS256, AlgES256:
+		key, err := v.publicKey(h.Kid)
+		if err != nil {
+			return err
+		}
+
+		return verifyAsymmetric(h.Alg, key, digest[:], signature)
+	}
+
+	// Never accept "none" or algorithms we did not configure
+	return fmt.Errorf("unsupported algorithm %q", h.Alg)
+}
+
+// publicKey returns the static key, or the JWKS key matching kid
+func (v *Verifier) publicKey(kid string) (crypto.PublicKey, error) {
+	if v.options.JWKS != nil {
+		if key, err := v.options.JWKS.Key(kid); err == nil || v.options.PublicKey == nil {
+			return key, err
+		}
+	}
+
+	if v.options.PublicKey == nil {
+		return nil, errors.New("asymmetric tokens are not accepted")
+	}
+
+	return v.options.PublicKey, nil
+}
+
+func verifyAsymmetric(alg string, key crypto.PublicKey, digest, signature []byte) error {
+	switch alg {
+	case AlgRS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("RS256 token but key is not RSA")
+		}
+
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest, signature); err != nil {
+			return errors.New("signature mismatch")
+		}
+
+		return nil
+
+	case AlgES256:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("ES256 token but key is not ECDSA")
+		}
+
+		// JWS encodes ECDSA signatures as r || s, 32 bytes each for P-256
+		if len(signature) != 64 {
+			return errors.New("bad ES256 signature length")
+		}
+
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("signature mismatch")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+func (v *Verifier) verifyClaims(claims Claims) error {
+	now := time.Now()
+
+	exp, ok := claims.time("exp")
+	if !ok {
+		return errors.New("missing exp claim")
+	}
+	if now.After(exp.Add(clockSkew)) {
+		return errors.New("token expired")
+	}
+
+	if nbf, ok := claims.time("nbf"); ok && now.Add(clockSkew).Before(nbf) {
+		return errors.New("token not valid yet")
+	}
+
+	if v.options.Issuer != "" && claims.Issuer() != v.options.Issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer())
+	}
+
+	if v.options.Audience != "" && !slices.Contains(claims.Audience(), v.options.Audience) {
+		return fmt.Errorf("token not issued for audience %q", v.options.Audience)
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, target)
+}
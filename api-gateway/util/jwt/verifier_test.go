@@ -0,0 +1,232 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test-secret")
+
+// signer signs the header and claims segments of a token
+type signer func(t *testing.T, signed []byte) []byte
+
+func hs256(secret []byte) signer {
+	return func(t *testing.T, signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func es256(key *ecdsa.PrivateKey) signer {
+	return func(t *testing.T, signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// r || s, 32 bytes each
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature
+	}
+}
+
+func unsigned(t *testing.T, signed []byte) []byte {
+	return nil
+}
+
+// newToken encodes and signs a token with the given algorithm in its header
+func newToken(t *testing.T, alg string, claims map[string]any, sign signer) string {
+	t.Helper()
+
+	encode := func(value any) string {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(map[string]string{"alg": alg, "typ": "JWT"}) + "." + encode(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(t, []byte(signed)))
+}
+
+// validClaims expire in an hour; changes are applied on a copy
+func validClaims(changes map[string]any) map[string]any {
+	claims := map[string]any{"sub": "alice", "iss": "issuer", "aud": "gateway", "exp": time.Now().Add(time.Hour).Unix()}
+	for name, value := range changes {
+		claims[name] = value
+	}
+	return claims
+}
+
+func TestVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secretOnly, err := NewVerifier(Options{Secret: testSecret, Issuer: "issuer", Audience: "gateway"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyOnly, err := NewVerifier(Options{PublicKey: &ecKey.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		token    string
+		wantErr  string // Empty when the token must be accepted
+	}{
+		{
+			name:     "valid HS256 token",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(nil), hs256(testSecret)),
+		},
+		{
+			name:     "valid ES256 token",
+			verifier: keyOnly,
+			token:    newToken(t, AlgES256, validClaims(nil), es256(ecKey)),
+		},
+		{
+			name:     "HS256 signed with another secret",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(nil), hs256([]byte("other-secret"))),
+			wantErr:  "signature mismatch",
+		},
+		{
+			name:     "ES256 signed with another key",
+			verifier: keyOnly,
+			token:    newToken(t, AlgES256, validClaims(nil), es256(otherKey)),
+			wantErr:  "signature mismatch",
+		},
+		{
+			name:     "claims changed after signing",
+			verifier: secretOnly,
+			token: func() string {
+				parts := strings.Split(newToken(t, AlgHS256, validClaims(nil), hs256(testSecret)), ".")
+				forged := strings.Split(newToken(t, AlgHS256, validClaims(map[string]any{"sub": "mallory"}), unsigned), ".")
+				return parts[0] + "." + forged[1] + "." + parts[2]
+			}(),
+			wantErr: "signature mismatch",
+		},
+		{
+			name:     "alg none",
+			verifier: secretOnly,
+			token:    newToken(t, "none", validClaims(nil), unsigned),
+			wantErr:  `unsupported algorithm "none"`,
+		},
+		{
+			name:     "HS256 token for a verifier without a secret",
+			verifier: keyOnly,
+			token:    newToken(t, AlgHS256, validClaims(nil), hs256(testSecret)),
+			wantErr:  "HS256 tokens are not accepted",
+		},
+		{
+			name:     "ES256 token for a verifier without a public key",
+			verifier: secretOnly,
+			token:    newToken(t, AlgES256, validClaims(nil), es256(ecKey)),
+			wantErr:  "asymmetric tokens are not accepted",
+		},
+		{
+			name:     "RS256 header with an ECDSA key",
+			verifier: keyOnly,
+			token:    newToken(t, AlgRS256, validClaims(nil), es256(ecKey)),
+			wantErr:  "RS256 token but key is not RSA",
+		},
+		{
+			name:     "expired",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(map[string]any{"exp": now.Add(-time.Hour).Unix()}), hs256(testSecret)),
+			wantErr:  "token expired",
+		},
+		{
+			name:     "expired within the clock skew",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(map[string]any{"exp": now.Add(-clockSkew / 2).Unix()}), hs256(testSecret)),
+		},
+		{
+			name:     "missing exp",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(map[string]any{"exp": nil}), hs256(testSecret)),
+			wantErr:  "missing exp claim",
+		},
+		{
+			name:     "not valid yet",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(map[string]any{"nbf": now.Add(time.Hour).Unix()}), hs256(testSecret)),
+			wantErr:  "token not valid yet",
+		},
+		{
+			name:     "not valid yet within the clock skew",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(map[string]any{"nbf": now.Add(clockSkew / 2).Unix()}), hs256(testSecret)),
+		},
+		{
+			name:     "unexpected issuer",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(map[string]any{"iss": "other"}), hs256(testSecret)),
+			wantErr:  `unexpected issuer "other"`,
+		},
+		{
+			name:     "other audience",
+			verifier: secretOnly,
+			token:    newToken(t, AlgHS256, validClaims(map[string]any{"aud": []string{"billing"}}), hs256(testSecret)),
+			wantErr:  `token not issued for audience "gateway"`,
+		},
+		{
+			name:     "two segments",
+			verifier: secretOnly,
+			token:    "eyJhbGciOiJIUzI1NiJ9.e30",
+			wantErr:  "malformed token",
+		},
+		{
+			name:     "header not base64",
+			verifier: secretOnly,
+			token:    "not base64!.e30.c2ln",
+			wantErr:  "bad header",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims, err := test.verifier.Verify(test.token)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				if claims.Subject() != "alice" {
+					t.Errorf("subject = %q, want alice", claims.Subject())
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidToken) || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("Verify error = %v, want an invalid token error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package latency
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxSamples caps the samples kept per instance, so a busy instance
+// is described by its most recent requests within the window
+const maxSamples = 2048
+
+// Tracker keeps the round-trip times of requests to service instances
+// and computes rolling statistics over a time window
+type Tracker struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+}
+
+type seriesKey struct {
+	service  string
+	instance string
+}
+
+// series is a ring buffer of the latest samples of one instance
+type series struct {
+	samples []sample
+	next    int
+}
+
+type sample struct {
+	at     time.Time
+	rtt    time.Duration
+	failed bool
+}
+
+// Stats are the statistics of the samples within a window
+type Stats struct {
+	Count  int     `json:"count"`
+	Errors int     `json:"errors"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MeanMs float64 `json:"mean_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// ServiceStats are the statistics of a service and each of its instances
+type ServiceStats struct {
+	Service   string          `json:"service"`
+	Stats     Stats           `json:"stats"`
+	Instances []InstanceStats `json:"instances"`
+}
+
+// InstanceStats are the statistics of one instance
+type InstanceStats struct {
+	Instance string `json:"instance"`
+	Stats    Stats  `json:"stats"`
+}
+
+// NewTracker creates a tracker keeping samples for window
+func NewTracker(window time.Duration) *Tracker {
+	return &Tracker{
+		window: window,
+		series: make(map[seriesKey]*series),
+	}
+}
+
+// Window returns the longest window statistics can be computed over
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+// Record adds the round-trip time of one request to an instance
+func (t *Tracker) Record(service, instance string, rtt time.Duration, failed bool) {
+	key := seriesKey{service: service, instance: instance}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.series[key]
+	if !ok {
+		s = &series{}
+		t.series[key] = s
+	}
+
+	entry := sample{at: time.Now(), rtt: rtt, failed: failed}
+	if len(s.samples) < maxSamples {
+		s.samples = append(s.samples, entry)
+		return
+	}
+
+	s.samples[s.next] = entry
+	s.next = (s.next + 1) % maxSamples
+}
+
+// Instance returns the statistics of one instance over the full window
+func (t *Tracker) Instance(service, instance string) Stats {
+	since := time.Now().Add(-t.window)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.series[seriesKey{service: service, instance: instance}]
+	if !ok {
+		return Stats{}
+	}
+
+	return compute(s.since(since))
+}
+
+// Snapshot returns the statistics of every service and instance with samples
+// in the last window (capped at the tracker's window), ordered by name.
+// Instances without recent samples are forgotten.
+func (t *Tracker) Snapshot(window time.Duration) []ServiceStats {
+	if window <= 0 || window > t.window {
+		window = t.window
+	}
+
+	now := time.Now()
+	since := now.Add(-window)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byService := make(map[string]*ServiceStats)
+	serviceSamples := make(map[string][]sample)
+
+	for key, s := range t.series {
+		// Nothing left within the tracker's window
+		if len(s.since(now.Add(-t.window))) == 0 {
+			delete(t.series, key)
+			continue
+		}
+
+		samples := s.since(since)
+		if len(samples) == 0 {
+			continue
+		}
+
+		stats, ok := byService[key.service]
+		if !ok {
+			stats = &ServiceStats{Service: key.service}
+			byService[key.service] = stats
+		}
+
+		stats.Instances = append(stats.Instances, InstanceStats{Instance: key.instance, Stats: compute(samples)})
+		serviceSamples[key.service] = append(serviceSamples[key.service], samples...)
+	}
+
+	result := make([]ServiceStats, 0, len(byService))
+	for service, stats := range byService {
+		stats.Stats = compute(serviceSamples[service])
+		sort.Slice(stats.Instances, func(i, j int) bool { return stats.Instances[i].Instance < stats.Instances[j].Instance })
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Service < result[j].Service })
+
+	return result
+}
+
+// since returns the samples taken after a point in time
+func (s *series) since(since time.Time) []sample {
+	samples := make([]sample, 0, len(s.samples))
+	for _, entry := range s.samples {
+		if entry.at.After(since) {
+			samples = append(samples, entry)
+		}
+	}
+
+	return samples
+}
+
+func compute(samples []sample) Stats {
+	stats := Stats{Count: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	rtts := make([]float64, len(samples))
+	var total float64
+	for i, entry := range samples {
+		rtts[i] = Milliseconds(entry.rtt)
+		total += rtts[i]
+		if entry.failed {
+			stats.Errors++
+		}
+	}
+	sort.Float64s(rtts)
+
+	stats.P50Ms = percentile(rtts, 0.50)
+	stats.P95Ms = percentile(rtts, 0.95)
+	stats.P99Ms = percentile(rtts, 0.99)
+	stats.MeanMs = math.Round(total/float64(len(rtts))*1000) / 1000
+	stats.MaxMs = rtts[len(rtts)-1]
+
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return sorted[rank]
+}
+
+// Milliseconds converts a duration to fractional milliseconds for JSON responses
+func Milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
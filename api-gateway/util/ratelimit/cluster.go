@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// reportIntervals is how many sync intervals a report keeps, so a replica that
+// misses a few reads of another replica's report does not lose its usage
+const reportIntervals = 5
+
+// Usage is the number of tokens taken from one bucket, with the bucket's limits
+// so other replicas can create the bucket if they have not seen the consumer yet
+type Usage struct {
+	Tokens            float64 `json:"tokens"`
+	RequestsPerSecond float64 `json:"rps"`
+	Burst             int     `json:"burst"`
+}
+
+// Interval is the usage of one replica during one sync interval
+type Interval struct {
+	Seq   int64            `json:"seq"` // End of the interval in Unix nanoseconds
+	Usage map[string]Usage `json:"usage"`
+}
+
+// Report is the record a replica publishes to share its usage
+type Report struct {
+	Replica   string     `json:"replica"`
+	Intervals []Interval `json:"intervals"` // The last few intervals with usage, oldest first
+}
+
+// Cluster shares the usage of a Limiter with the other gateway replicas.
+// Every replica publishes the tokens it took in its own report and deducts
+// the tokens the other replicas took from its local buckets, so the limits
+// hold approximately across the cluster, within about one sync interval.
+type Cluster struct {
+	limiter   *Limiter
+	replicaID string
+
+	report  Report
+	lastSeq map[string]int64 // replica -> last interval deducted
+}
+
+// NewCluster starts tracking the usage of limiter for the replica replicaID
+func NewCluster(limiter *Limiter, replicaID string) *Cluster {
+	limiter.mu.Lock()
+	limiter.tracking = true
+	limiter.usage = make(map[string]Usage)
+	limiter.mu.Unlock()
+
+	return &Cluster{
+		limiter:   limiter,
+		replicaID: replicaID,
+		report:    Report{Replica: replicaID},
+		lastSeq:   make(map[string]int64),
+	}
+}
+
+// ReplicaID returns the id of this replica
+func (c *Cluster) ReplicaID() string {
+	return c.replicaID
+}
+
+// NextReport drains the local usage into this replica's report.
+// It returns nil when nothing was used since the last report.
+func (c *Cluster) NextReport() ([]byte, error) {
+	usage := c.limiter.drain()
+	if len(usage) == 0 {
+		return nil, nil
+	}
+
+	c.report.Intervals = append(c.report.Intervals, Interval{Seq: time.Now().UnixNano(), Usage: usage})
+	if len(c.report.Intervals) > reportIntervals {
+		c.report.Intervals = c.report.Intervals[len(c.report.Intervals)-reportIntervals:]
+	}
+
+	value, err := json.Marshal(c.report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rate limit report: %w", err)
+	}
+
+	return value, nil
+}
+
+// Merge deducts the usage reported by other replicas that was not deducted yet.
+// reports are keyed by replica id. It returns the replicas whose reports were
+// not updated for longer than staleAfter, so their records can be removed.
+func (c *Cluster) Merge(reports map[string][]byte, staleAfter time.Duration) []string {
+	now := time.Now()
+	var stale []string
+
+	for replica, value := range reports {
+		if replica == c.replicaID || len(value) == 0 {
+			continue
+		}
+
+		var report Report
+		if err := json.Unmarshal(value, &report); err != nil || len(report.Intervals) == 0 {
+			continue
+		}
+
+		newest := report.Intervals[len(report.Intervals)-1].Seq
+		if now.Sub(time.Unix(0, newest)) > staleAfter {
+			stale = append(stale, replica)
+		}
+
+		for _, interval := range report.Intervals {
+			if interval.Seq > c.lastSeq[replica] {
+				c.limiter.deduct(interval.Usage)
+			}
+		}
+
+		c.lastSeq[replica] = newest
+	}
+
+	// Forget replicas whose reports are gone
+	for replica := range c.lastSeq {
+		if _, ok := reports[replica]; !ok {
+			delete(c.lastSeq, replica)
+		}
+	}
+
+	return stale
+}
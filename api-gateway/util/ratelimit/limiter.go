@@ -0,0 +1,182 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are removed
+const sweepInterval = time.Minute
+
+// Check is one token bucket a request must take a token from
+type Check struct {
+	Scope             string  // e.g. "global", "route", "service"; reported when the check rejects
+	Key               string  // Bucket key, unique per scope and consumer
+	RequestsPerSecond float64 // Refill rate
+	Burst             int     // Bucket capacity
+}
+
+// Result describes the most restrictive bucket of a request
+type Result struct {
+	Allowed    bool
+	Scope      string        // Scope of the reported bucket
+	Limit      int           // Capacity of the reported bucket
+	Remaining  int           // Tokens left in the reported bucket
+	Reset      time.Duration // Time until the reported bucket is full again
+	RetryAfter time.Duration // Time until a rejected request may be retried
+}
+
+// Limiter holds token buckets in memory
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	// Tokens taken since the last drain, shared with other replicas in cluster mode
+	tracking bool
+	usage    map[string]Usage
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+// NewLimiter creates an empty limiter
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take takes one token from every bucket, or from none when any bucket is empty
+func (l *Limiter) Take(checks ...Check) Result {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	buckets := make([]*bucket, len(checks))
+	for i, check := range checks {
+		buckets[i] = l.refill(check, now)
+	}
+
+	// Rejected: report the bucket that takes longest to allow a retry
+	var rejected *Result
+	for i, b := range buckets {
+		if b.tokens >= 1 {
+			continue
+		}
+
+		result := b.result(checks[i].Scope)
+		result.RetryAfter = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		if rejected == nil || result.RetryAfter > rejected.RetryAfter {
+			rejected = &result
+		}
+	}
+
+	if rejected != nil {
+		return *rejected
+	}
+
+	// Allowed: report the bucket with the fewest tokens left
+	var allowed Result
+	for i, b := range buckets {
+		b.tokens--
+		l.track(checks[i])
+
+		result := b.result(checks[i].Scope)
+		if i == 0 || result.Remaining < allowed.Remaining {
+			allowed = result
+		}
+	}
+	allowed.Allowed = true
+
+	return allowed
+}
+
+// track records a token taken from the bucket of check
+func (l *Limiter) track(check Check) {
+	if !l.tracking {
+		return
+	}
+
+	usage := l.usage[check.Key]
+	usage.Tokens++
+	usage.RequestsPerSecond = check.RequestsPerSecond
+	usage.Burst = check.Burst
+	l.usage[check.Key] = usage
+}
+
+// drain returns and resets the tokens taken since the last drain
+func (l *Limiter) drain() map[string]Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	usage := l.usage
+	l.usage = make(map[string]Usage)
+
+	return usage
+}
+
+// deduct removes tokens taken by other replicas from the local buckets
+func (l *Limiter) deduct(usage map[string]Usage) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, used := range usage {
+		b := l.refill(Check{Key: key, RequestsPerSecond: used.RequestsPerSecond, Burst: used.Burst}, now)
+
+		// A bucket may go into debt, but never by more than one burst
+		b.tokens = math.Max(-b.burst, b.tokens-used.Tokens)
+	}
+}
+
+// refill returns the bucket of check with the tokens earned since its last use
+func (l *Limiter) refill(check Check, now time.Time) *bucket {
+	b, ok := l.buckets[check.Key]
+	if !ok {
+		b = &bucket{tokens: float64(check.Burst), last: now}
+		l.buckets[check.Key] = b
+	}
+
+	// Limits can change at runtime
+	b.rate = check.RequestsPerSecond
+	b.burst = float64(check.Burst)
+
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	return b
+}
+
+// sweep removes buckets that have refilled completely, they behave like new ones
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *bucket) result(scope string) Result {
+	return Result{
+		Scope:     scope,
+		Limit:     int(b.burst),
+		Remaining: int(math.Max(0, math.Floor(b.tokens))),
+		Reset:     time.Duration((b.burst - b.tokens) / b.rate * float64(time.Second)),
+	}
+}
@@ -0,0 +1,164 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Balancer strategies
+const (
+	StrategyRandom     = "random"
+	StrategyRoundRobin = "round_robin"
+	StrategyWeighted   = "weighted" // By the Consul weights of the instances
+	StrategyNearest    = "nearest"  // Weighted among the instances closest by network round-trip time
+)
+
+// Health policies, which instances a route balances over by the status of their checks
+const (
+	HealthPassing = "passing" // Only instances whose checks all pass
+	HealthWarning = "warning" // Passing and warning instances
+	HealthAny     = "any"     // Every instance that is not in maintenance, even critical ones
+)
+
+// Settings is the gateway behaviour that can be changed at runtime
+// through Consul KV, without restarting the gateway
+type Settings struct {
+	Revision  uint64           `json:"revision"` // Consul KV index the settings were loaded from (0 = defaults)
+	Timeouts  Timeouts         `json:"timeouts"`
+	Balancer  Balancer         `json:"balancer"`
+	RateLimit RateLimit        `json:"rate_limit"` // Per consumer, across all routes
+	Routes    map[string]Route `json:"routes"`
+
+	Services map[string]ServiceSettings `json:"services"` // Keyed by Consul service name
+}
+
+// Timeouts config
+type Timeouts struct {
+	Upstream Duration `json:"upstream"` // Max time for a request to a service
+}
+
+// Balancer config
+type Balancer struct {
+	Strategy      string   `json:"strategy"`       // random, round_robin, weighted or nearest
+	LoadSmoothing Duration `json:"load_smoothing"` // How gradually the weighted balancer follows the load instances report
+	Nearest       Nearest  `json:"nearest"`
+}
+
+// Nearest config of the nearest balancer
+type Nearest struct {
+	Tolerance     Duration `json:"tolerance"`       // Instances this much farther than the closest one count as equally near
+	SpillOverLoad float64  `json:"spill_over_load"` // Reported load at which the nearest instances are saturated and farther ones take traffic too
+}
+
+// RateLimit config
+type RateLimit struct {
+	Enabled           bool    `json:"enabled"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+}
+
+// Route configures how a name in /api/ping/{name} is routed
+type Route struct {
+	Service   string     `json:"service"`              // Consul service name (default: the route name)
+	Timeout   Duration   `json:"timeout,omitempty"`    // Overrides timeouts.upstream
+	Balancer  string     `json:"balancer,omitempty"`   // Overrides balancer.strategy
+	Health    string     `json:"health,omitempty"`     // Health policy: passing (default), warning or any
+	RateLimit *RateLimit `json:"rate_limit,omitempty"` // Additional per consumer limit for this route
+
+	// Weighted and nearest balancing follow the load instances report
+	LoadSmoothing Duration `json:"load_smoothing,omitempty"` // Overrides balancer.load_smoothing
+	Nearest       *Nearest `json:"nearest,omitempty"`        // Overrides the fields of balancer.nearest that are set
+
+	// Consul prepared query (name or id) resolving the instances instead of a service lookup;
+	// its tag filters and failover policy apply
+	Query string `json:"query,omitempty"`
+
+	// Datacenters, tried in order until one has healthy instances
+	Datacenter string   `json:"datacenter,omitempty"` // Preferred datacenter (default: the local one)
+	Failover   []string `json:"failover,omitempty"`   // Datacenters to fail over to; "*" for all others, nearest first
+
+	// Authorization, checked against the verified JWT when JWT auth is enabled
+	RequiredScopes []string          `json:"required_scopes,omitempty"` // Scopes the token must all carry
+	RequiredClaims map[string]string `json:"required_claims,omitempty"` // Claims the token must carry with these values
+}
+
+// ServiceSettings applies to every route of a Consul service
+type ServiceSettings struct {
+	RateLimit *RateLimit `json:"rate_limit,omitempty"` // Additional per consumer limit for this service
+}
+
+// Defaults returns the settings used until a valid revision is read from Consul
+func Defaults() *Settings {
+	return &Settings{
+		Timeouts: Timeouts{Upstream: Duration(30 * time.Second)},
+		Balancer: Balancer{
+			Strategy:      StrategyRandom,
+			LoadSmoothing: Duration(10 * time.Second),
+			Nearest:       Nearest{Tolerance: Duration(2 * time.Millisecond), SpillOverLoad: 0.8},
+		},
+		Routes:   map[string]Route{},
+		Services: map[string]ServiceSettings{},
+	}
+}
+
+// Route returns the effective route for a name, with all defaults resolved
+// Names without a configured route are routed to the service of the same name
+func (s *Settings) Route(name string) Route {
+	route, ok := s.Routes[name]
+	if !ok || route.Service == "" {
+		route.Service = name
+	}
+
+	if route.Timeout == 0 {
+		route.Timeout = s.Timeouts.Upstream
+	}
+
+	if route.Balancer == "" {
+		route.Balancer = s.Balancer.Strategy
+	}
+
+	if route.LoadSmoothing == 0 {
+		route.LoadSmoothing = s.Balancer.LoadSmoothing
+	}
+
+	nearest := s.Balancer.Nearest
+	if route.Nearest != nil {
+		if route.Nearest.Tolerance != 0 {
+			nearest.Tolerance = route.Nearest.Tolerance
+		}
+		if route.Nearest.SpillOverLoad != 0 {
+			nearest.SpillOverLoad = route.Nearest.SpillOverLoad
+		}
+	}
+	route.Nearest = &nearest
+
+	if route.Health == "" {
+		route.Health = HealthPassing
+	}
+
+	return route
+}
+
+// Duration is a time.Duration that is written as "5s" in JSON
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %w", err)
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxTimeout caps configured timeouts so a typo cannot hang requests forever
+const maxTimeout = 5 * time.Minute
+
+// maxLoadSmoothing caps the load smoothing, beyond it the balancer would hardly react to load
+const maxLoadSmoothing = 10 * time.Minute
+
+// Store holds the current settings and swaps them atomically
+// Requests read the settings once, so in-flight requests keep
+// using the revision they started with
+type Store struct {
+	current atomic.Pointer[Settings]
+}
+
+// NewStore creates a store holding the default settings
+func NewStore() *Store {
+	store := &Store{}
+	store.current.Store(Defaults())
+
+	return store
+}
+
+// Current returns the settings in effect
+func (s *Store) Current() *Settings {
+	return s.current.Load()
+}
+
+// Apply parses and validates a new revision read from the KV prefix.
+// The keys of entries are relative to the prefix:
+//
+//	timeouts         {"upstream": "10s"}
+//	balancer         {"strategy": "weighted", "load_smoothing": "10s"}
+//	rate_limit       {"enabled": true, "requests_per_second": 50, "burst": 100}
+//	routes/<name>    {"service": "service-a", "timeout": "2s", "failover": ["dc2"]}
+//	routes/<name>    {"query": "service-a-eu", "timeout": "2s"}
+//	services/<name>  {"rate_limit": {"enabled": true, "requests_per_second": 20, "burst": 40}}
+//
+// An invalid revision is rejected and the last good settings stay in effect.
+func (s *Store) Apply(revision uint64, entries map[string][]byte) error {
+	next, err := Parse(revision, entries)
+	if err != nil {
+		return err
+	}
+
+	s.current.Store(next)
+	log.Printf("⚙️ Applied gateway settings revision %d (%d routes)", revision, len(next.Routes))
+
+	return nil
+}
+
+// Parse builds settings from KV entries on top of the defaults and validates them
+func Parse(revision uint64, entries map[string][]byte) (*Settings, error) {
+	next := Defaults()
+	next.Revision = revision
+
+	for key, value := range entries {
+		// Folders and empty keys carry no settings
+		if len(value) == 0 {
+			continue
+		}
+
+		var err error
+		switch {
+		case key == "timeouts":
+			err = json.Unmarshal(value, &next.Timeouts)
+		case key == "balancer":
+			err = json.Unmarshal(value, &next.Balancer)
+		case key == "rate_limit":
+			err = json.Unmarshal(value, &next.RateLimit)
+		case strings.HasPrefix(key, "routes/"):
+			var route Route
+			err = json.Unmarshal(value, &route)
+			next.Routes[strings.TrimPrefix(key, "routes/")] = route
+		case strings.HasPrefix(key, "services/"):
+			var service ServiceSettings
+			err = json.Unmarshal(value, &service)
+			next.Services[strings.TrimPrefix(key, "services/")] = service
+		default:
+			log.Printf("⚠️ Ignoring unknown gateway settings key %q", key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid settings key %q: %w", key, err)
+		}
+	}
+
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+// Validate checks that the settings can be applied
+func (s *Settings) Validate() error {
+	var problems []string
+
+	if err := validateTimeout(s.Timeouts.Upstream); err != nil {
+		problems = append(problems, "timeouts.upstream: "+err.Error())
+	}
+
+	if err := validateStrategy(s.Balancer.Strategy); err != nil {
+		problems = append(problems, "balancer.strategy: "+err.Error())
+	}
+
+	if err := validateSmoothing(s.Balancer.LoadSmoothing); err != nil {
+		problems = append(problems, "balancer.load_smoothing: "+err.Error())
+	}
+
+	if err := validateNearest(s.Balancer.Nearest); err != nil {
+		problems = append(problems, "balancer.nearest: "+err.Error())
+	}
+
+	if err := validateRateLimit(s.RateLimit); err != nil {
+		problems = append(problems, "rate_limit: "+err.Error())
+	}
+
+	for name, route := range s.Routes {
+		if name == "" || strings.Contains(name, "/") {
+			problems = append(problems, fmt.Sprintf("routes/%s: invalid route name", name))
+		}
+
+		if route.Timeout != 0 {
+			if err := validateTimeout(route.Timeout); err != nil {
+				problems = append(problems, fmt.Sprintf("routes/%s.timeout: %v", name, err))
+			}
+		}
+
+		if route.Balancer != "" {
+			if err := validateStrategy(route.Balancer); err != nil {
+				problems = append(problems, fmt.Sprintf("routes/%s.balancer: %v", name, err))
+			}
+		}
+
+		if route.RateLimit != nil {
+			if err := validateRateLimit(*route.RateLimit); err != nil {
+				problems = append(problems, fmt.Sprintf("routes/%s.rate_limit: %v", name, err))
+			}
+		}
+
+		if route.LoadSmoothing != 0 {
+			if err := validateSmoothing(route.LoadSmoothing); err != nil {
+				problems = append(problems, fmt.Sprintf("routes/%s.load_smoothing: %v", name, err))
+			}
+		}
+
+		if route.Nearest != nil {
+			// Unset fields are taken from balancer.nearest
+			nearest := *route.Nearest
+			if nearest.SpillOverLoad == 0 {
+				nearest.SpillOverLoad = s.Balancer.Nearest.SpillOverLoad
+			}
+
+			if err := validateNearest(nearest); err != nil {
+				problems = append(problems, fmt.Sprintf("routes/%s.nearest: %v", name, err))
+			}
+		}
+
+		switch route.Health {
+		case "", HealthPassing, HealthWarning, HealthAny:
+		default:
+			problems = append(problems, fmt.Sprintf("routes/%s.health: must be %q, %q or %q, got %q", name, HealthPassing, HealthWarning, HealthAny, route.Health))
+		}
+
+		if route.Query != "" && route.Health != "" {
+			problems = append(problems, fmt.Sprintf("routes/%s.health: must not be set together with query, set only_passing in the prepared query", name))
+		}
+
+		if route.Query != "" && len(route.Failover) > 0 {
+			problems = append(problems, fmt.Sprintf("routes/%s.failover: must not be set together with query, set the failover in the prepared query", name))
+		}
+
+		for _, datacenter := range route.Failover {
+			if strings.TrimSpace(datacenter) == "" {
+				problems = append(problems, fmt.Sprintf("routes/%s.failover: empty datacenter name", name))
+			}
+		}
+	}
+
+	for name, service := range s.Services {
+		if name == "" || strings.Contains(name, "/") {
+			problems = append(problems, fmt.Sprintf("services/%s: invalid service name", name))
+		}
+
+		if service.RateLimit != nil {
+			if err := validateRateLimit(*service.RateLimit); err != nil {
+				problems = append(problems, fmt.Sprintf("services/%s.rate_limit: %v", name, err))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid gateway settings: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func validateTimeout(timeout Duration) error {
+	if timeout <= 0 || time.Duration(timeout) > maxTimeout {
+		return fmt.Errorf("must be between 0s and %s, got %s", maxTimeout, time.Duration(timeout))
+	}
+
+	return nil
+}
+
+func validateStrategy(strategy string) error {
+	switch strategy {
+	case StrategyRandom, StrategyRoundRobin, StrategyWeighted, StrategyNearest:
+		return nil
+	}
+
+	return fmt.Errorf("must be %q, %q, %q or %q, got %q", StrategyRandom, StrategyRoundRobin, StrategyWeighted, StrategyNearest, strategy)
+}
+
+func validateNearest(nearest Nearest) error {
+	if nearest.Tolerance < 0 || time.Duration(nearest.Tolerance) > maxTimeout {
+		return fmt.Errorf("tolerance must be between 0s and %s, got %s", maxTimeout, time.Duration(nearest.Tolerance))
+	}
+
+	if nearest.SpillOverLoad <= 0 || nearest.SpillOverLoad > 1 {
+		return fmt.Errorf("spill_over_load must be above 0 and at most 1, got %g", nearest.SpillOverLoad)
+	}
+
+	return nil
+}
+
+func validateSmoothing(smoothing Duration) error {
+	if smoothing < 0 || time.Duration(smoothing) > maxLoadSmoothing {
+		return fmt.Errorf("must be between 0s and %s, got %s", maxLoadSmoothing, time.Duration(smoothing))
+	}
+
+	return nil
+}
+
+func validateRateLimit(rateLimit RateLimit) error {
+	if !rateLimit.Enabled {
+		return nil
+	}
+
+	if rateLimit.RequestsPerSecond <= 0 {
+		return fmt.Errorf("requests_per_second must be positive, got %g", rateLimit.RequestsPerSecond)
+	}
+
+	if rateLimit.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1, got %d", rateLimit.Burst)
+	}
+
+	return nil
+}
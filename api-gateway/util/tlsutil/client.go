@@ -0,0 +1,117 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"time"
+)
+
+// ClientConfig returns a TLS client configuration that always presents the
+// most recently loaded client certificate and verifies servers against the
+// most recently loaded CA pool (or the system roots when no CA file is set).
+// The server certificate must be valid for serverName, a host name or IP address.
+func (r *Reloader) ClientConfig(serverName string, insecureSkipVerify bool) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName, // Sent as SNI unless it is an IP address
+
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			loaded := r.current.Load()
+			if loaded.certificate == nil {
+				// No client certificate configured, continue without one
+				return &tls.Certificate{}, nil
+			}
+
+			return loaded.certificate, nil
+		},
+
+		// Verification is done in VerifyConnection so that a reloaded CA pool
+		// applies to new connections without rebuilding the transport
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if insecureSkipVerify {
+				return nil
+			}
+
+			return verifyServer(state, r.current.Load().caPool, serverName)
+		},
+	}
+}
+
+// DialTLSContext returns a dial function for http.Transport.DialTLSContext.
+// Each connection verifies the server against serverName, or against the dialed
+// host when serverName is empty, so instances addressed by IP are checked
+// against the IP addresses in their certificate.
+func (r *Reloader) DialTLSContext(serverName string, insecureSkipVerify bool) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		name := serverName
+		if name == "" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			name = host
+		}
+
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		// The transport only reports the TLS handshake of connections it secures itself
+		trace := httptrace.ContextClientTrace(ctx)
+		if trace != nil && trace.TLSHandshakeStart != nil {
+			trace.TLSHandshakeStart()
+		}
+
+		tlsConn := tls.Client(conn, r.ClientConfig(name, insecureSkipVerify))
+		err = tlsConn.HandshakeContext(ctx)
+
+		if trace != nil && trace.TLSHandshakeDone != nil {
+			trace.TLSHandshakeDone(tlsConn.ConnectionState(), err)
+		}
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return tlsConn, nil
+	}
+}
+
+// verifyServer verifies the server certificate chain against roots,
+// and that the certificate is valid for serverName (DNS or IP SAN)
+func verifyServer(state tls.ConnectionState, roots *x509.CertPool, serverName string) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+
+	// Without a name any certificate from a trusted CA would pass, fail closed instead
+	if serverName == "" {
+		return errors.New("failed to verify server certificate: no server name to verify it against")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       serverName, // Checked against the IP SANs when it is an IP address
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify server certificate: %w", err)
+	}
+
+	return nil
+}
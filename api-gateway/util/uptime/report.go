@@ -0,0 +1,166 @@
+package uptime
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ServiceUptime is the availability of a service over a window
+// The service is up in a probe round when at least one of its instances answered
+type ServiceUptime struct {
+	Service       string           `json:"service"`
+	UptimePercent float64          `json:"uptime_percent"`
+	Rounds        int              `json:"rounds"`
+	Up            bool             `json:"up"` // Result of the last round
+	LastProbe     time.Time        `json:"last_probe"`
+	Instances     []InstanceUptime `json:"instances"`
+	Incidents     []Incident       `json:"incidents"` // Newest first
+}
+
+// InstanceUptime is the availability of one instance over a window
+type InstanceUptime struct {
+	Instance      string  `json:"instance"`
+	UptimePercent float64 `json:"uptime_percent"`
+	Probes        int     `json:"probes"`
+	Up            bool    `json:"up"`
+}
+
+// Incident is a period in which a service was down
+type Incident struct {
+	Started  time.Time  `json:"started"`
+	Ended    *time.Time `json:"ended,omitempty"` // Nil while ongoing
+	Duration string     `json:"duration"`
+	Error    string     `json:"error"` // First error of the incident
+}
+
+// round is one probe round of a service
+type round struct {
+	time  time.Time
+	up    bool
+	error string
+}
+
+// Summarize computes the uptime and incidents of every service from probe results
+func Summarize(results []Result, now time.Time) []ServiceUptime {
+	type instanceCount struct {
+		probes, up int
+		last       bool
+	}
+
+	rounds := make(map[string][]round)
+	instances := make(map[string]map[string]*instanceCount)
+
+	for _, result := range results {
+		// Results of one round share their time
+		serviceRounds := rounds[result.Service]
+		if n := len(serviceRounds); n == 0 || !serviceRounds[n-1].time.Equal(result.Time) {
+			serviceRounds = append(serviceRounds, round{time: result.Time})
+		}
+		current := &serviceRounds[len(serviceRounds)-1]
+		if result.Up {
+			current.up = true
+		} else if current.error == "" {
+			current.error = result.Error
+		}
+		rounds[result.Service] = serviceRounds
+
+		if result.Instance == "" {
+			continue
+		}
+
+		if instances[result.Service] == nil {
+			instances[result.Service] = make(map[string]*instanceCount)
+		}
+		count, ok := instances[result.Service][result.Instance]
+		if !ok {
+			count = &instanceCount{}
+			instances[result.Service][result.Instance] = count
+		}
+		count.probes++
+		count.last = result.Up
+		if result.Up {
+			count.up++
+		}
+	}
+
+	summaries := make([]ServiceUptime, 0, len(rounds))
+	for service, serviceRounds := range rounds {
+		summary := ServiceUptime{
+			Service:   service,
+			Rounds:    len(serviceRounds),
+			Instances: []InstanceUptime{},
+			Incidents: incidents(serviceRounds, now),
+		}
+
+		up := 0
+		for _, r := range serviceRounds {
+			if r.up {
+				up++
+			}
+		}
+		summary.UptimePercent = percent(up, len(serviceRounds))
+
+		last := serviceRounds[len(serviceRounds)-1]
+		summary.Up = last.up
+		summary.LastProbe = last.time
+
+		for instance, count := range instances[service] {
+			summary.Instances = append(summary.Instances, InstanceUptime{
+				Instance:      instance,
+				UptimePercent: percent(count.up, count.probes),
+				Probes:        count.probes,
+				Up:            count.last,
+			})
+		}
+		sort.Slice(summary.Instances, func(i, j int) bool {
+			return summary.Instances[i].Instance < summary.Instances[j].Instance
+		})
+
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Service < summaries[j].Service })
+
+	return summaries
+}
+
+// incidents turns consecutive down rounds into incidents, newest first
+// An incident ends at the first round the service was up again
+func incidents(rounds []round, now time.Time) []Incident {
+	list := []Incident{}
+
+	var current *Incident
+	for _, r := range rounds {
+		switch {
+		case !r.up && current == nil:
+			current = &Incident{Started: r.time, Error: r.error}
+
+		case r.up && current != nil:
+			ended := r.time
+			current.Ended = &ended
+			current.Duration = ended.Sub(current.Started).Round(time.Second).String()
+			list = append(list, *current)
+			current = nil
+		}
+	}
+
+	if current != nil {
+		current.Duration = now.Sub(current.Started).Round(time.Second).String()
+		list = append(list, *current)
+	}
+
+	// Newest first
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+
+	return list
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return math.Round(float64(part)/float64(total)*100000) / 1000
+}
@@ -0,0 +1,212 @@
+package uptime
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// compactInterval is how often the file is rewritten without expired results
+const compactInterval = 24 * time.Hour
+
+// Result is the outcome of one probe of a service instance
+// A round that found no healthy instance is recorded with an empty Instance
+type Result struct {
+	Time       time.Time `json:"time"`
+	Service    string    `json:"service"`
+	Instance   string    `json:"instance,omitempty"`
+	Up         bool      `json:"up"`
+	StatusCode int       `json:"status_code,omitempty"`
+	LatencyMs  float64   `json:"latency_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Store keeps probe results in an append-only JSON lines file and in memory.
+// Results older than the retention are dropped from memory right away and
+// from the file when it is compacted.
+type Store struct {
+	path      string
+	retention time.Duration
+
+	mu          sync.Mutex
+	file        *os.File
+	results     []Result // Ordered by time
+	lastCompact time.Time
+}
+
+// Open loads the results kept at path and compacts the file
+func Open(path string, retention time.Duration) (*Store, error) {
+	store := &Store{path: path, retention: retention}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	if err := store.compact(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Retention returns how long results are kept
+func (s *Store) Retention() time.Duration {
+	return s.retention
+}
+
+// Append stores the results of a probe round
+func (s *Store) Append(results ...Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results = append(s.results, results...)
+	s.expire()
+
+	for _, result := range results {
+		line, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode probe result: %w", err)
+		}
+
+		if _, err := s.file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write probe result: %w", err)
+		}
+	}
+
+	if time.Since(s.lastCompact) > compactInterval {
+		return s.compactLocked()
+	}
+
+	return nil
+}
+
+// Since returns the results recorded after a point in time
+func (s *Store) Since(since time.Time) []Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, result := range s.results {
+		if result.Time.After(since) {
+			return append([]Result(nil), s.results[i:]...)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// load reads the results still within the retention; unreadable lines are skipped
+func (s *Store) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open probe store %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	skipped := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			skipped++
+			continue
+		}
+
+		s.results = append(s.results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read probe store %s: %w", s.path, err)
+	}
+
+	if skipped > 0 {
+		log.Printf("⚠️ Skipped %d unreadable lines in probe store %s", skipped, s.path)
+	}
+
+	s.expire()
+
+	return nil
+}
+
+func (s *Store) compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compactLocked()
+}
+
+// compactLocked rewrites the file with the results in memory and keeps the rewritten
+// file open for appending. The current file stays in use until the rewrite is in place,
+// so a failed compaction does not stop later appends.
+func (s *Store) compactLocked() error {
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to compact probe store: %w", err)
+	}
+
+	if err := s.writeResults(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact probe store: %w", err)
+	}
+
+	// The open handle follows the file to its new name
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact probe store: %w", err)
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = tmp
+	s.lastCompact = time.Now()
+
+	return nil
+}
+
+// writeResults writes the results in memory to file and syncs it
+func (s *Store) writeResults(file *os.File) error {
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, result := range s.results {
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// expire drops the results older than the retention from memory
+func (s *Store) expire() {
+	cutoff := time.Now().Add(-s.retention)
+
+	drop := 0
+	for drop < len(s.results) && !s.results[drop].Time.After(cutoff) {
+		drop++
+	}
+
+	if drop > 0 {
+		s.results = append([]Result(nil), s.results[drop:]...)
+	}
+}
@@ -0,0 +1,107 @@
+package uptime
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAppendAfterFailedCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uptime.jsonl")
+
+	store, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.Append(Result{Time: time.Now(), Service: "service-a", Up: true}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	// A directory in place of the temporary file makes every compaction fail
+	if err := os.Mkdir(path+".tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	store.lastCompact = time.Time{}
+
+	if err := store.Append(Result{Time: time.Now(), Service: "service-b", Up: true}); err == nil {
+		t.Fatal("Append compacted although the temporary file cannot be created")
+	}
+
+	// The results are still written to the store file
+	if got := readServices(t, path); !slices.Equal(got, []string{"service-a", "service-b"}) {
+		t.Fatalf("file after failed compaction = %v, want [service-a service-b]", got)
+	}
+
+	// Once compaction works again, the store keeps appending to the compacted file
+	if err := os.Remove(path + ".tmp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(Result{Time: time.Now(), Service: "service-c", Up: false}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := store.Append(Result{Time: time.Now(), Service: "service-d", Up: true}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	if got := readServices(t, path); !slices.Equal(got, []string{"service-a", "service-b", "service-c", "service-d"}) {
+		t.Fatalf("file after compaction = %v, want [service-a service-b service-c service-d]", got)
+	}
+}
+
+func TestCompactionDropsExpiredResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uptime.jsonl")
+
+	old, _ := json.Marshal(Result{Time: time.Now().Add(-2 * time.Hour), Service: "expired"})
+	recent, _ := json.Marshal(Result{Time: time.Now().Add(-time.Minute), Service: "recent"})
+	content := string(old) + "\n" + "not json\n" + string(recent) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if got := readServices(t, path); !slices.Equal(got, []string{"recent"}) {
+		t.Fatalf("file after open = %v, want [recent]", got)
+	}
+
+	if err := store.Append(Result{Time: time.Now(), Service: "new", Up: true}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	if got := readServices(t, path); !slices.Equal(got, []string{"recent", "new"}) {
+		t.Fatalf("file after append = %v, want [recent new]", got)
+	}
+}
+
+// readServices returns the service of every result in the store file
+func readServices(t *testing.T, path string) []string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var services []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("unreadable line %q: %v", scanner.Text(), err)
+		}
+		services = append(services, result.Service)
+	}
+
+	return services
+}
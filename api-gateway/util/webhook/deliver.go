@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"text/template"
+	"time"
+
+	"api-gateway/util/catalog"
+)
+
+// parseTemplate parses a payload template; the json function quotes a value as JSON,
+// e.g. {"text": {{ printf "%s is %s" .Service .Status | json }}}
+func parseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	payload, err := template.New("payload").Funcs(template.FuncMap{
+		"json": func(value any) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", err)
+	}
+
+	return payload, nil
+}
+
+// render builds the JSON payload of an event
+func (t *target) render(event catalog.Event) ([]byte, error) {
+	if t.payload == nil {
+		return json.Marshal(event)
+	}
+
+	var body bytes.Buffer
+	if err := t.payload.Execute(&body, event); err != nil {
+		return nil, fmt.Errorf("failed to render webhook payload: %w", err)
+	}
+
+	if !json.Valid(body.Bytes()) {
+		return nil, fmt.Errorf("webhook template rendered invalid JSON: %s", body.String())
+	}
+
+	return body.Bytes(), nil
+}
+
+// deliverQueue delivers the queued events of a target one at a time, in order
+func (n *Notifier) deliverQueue(ctx context.Context, t *target) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-t.queue:
+			if err := n.deliver(ctx, t, event); err != nil {
+				deliveries.Inc(t.URL, "failed")
+				log.Printf("❌ Failed to deliver %s event of %s to %s: %v", event.Type, subject(event), t.URL, err)
+				continue
+			}
+
+			deliveries.Inc(t.URL, "delivered")
+			log.Printf("📣 Delivered %s event of %s to %s", event.Type, subject(event), t.URL)
+		}
+	}
+}
+
+// deliver posts an event, retrying with exponential backoff on network errors,
+// 429 and 5xx responses
+func (n *Notifier) deliver(ctx context.Context, t *target, event catalog.Event) error {
+	body, err := t.render(event)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.options.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, t, event, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt >= n.options.Retries {
+			return err
+		}
+
+		log.Printf("⚠️ Webhook %s failed (attempt %d), retrying in %s: %v", t.URL, attempt+1, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (n *Notifier) post(ctx context.Context, t *target, event catalog.Event, body []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Gateway-Event", string(event.Type))
+	request.Header.Set("X-Gateway-Event-ID", event.ID)
+	for name, value := range t.Headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	response.Body.Close()
+
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500 {
+		return true, fmt.Errorf("status %d", response.StatusCode)
+	}
+
+	if response.StatusCode >= 300 {
+		return false, fmt.Errorf("status %d", response.StatusCode)
+	}
+
+	return false, nil
+}
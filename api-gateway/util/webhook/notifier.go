@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"text/template"
+	"time"
+
+	"api-gateway/util/catalog"
+	"api-gateway/util/metrics"
+)
+
+// queueSize is how many events may wait for delivery to one target
+const queueSize = 64
+
+var deliveries = metrics.NewCounter(
+	"gateway_webhook_deliveries_total",
+	"Webhook deliveries by target and result (delivered, failed or dropped).",
+	"target", "result",
+)
+
+// Target is a URL notified about catalog events
+type Target struct {
+	URL      string
+	Events   []catalog.EventType // All when empty
+	Services []string            // All when empty
+	Headers  map[string]string
+	Template string // Go template of the JSON payload, the event itself when empty
+}
+
+// Options control how events are delivered
+type Options struct {
+	Cooldown     time.Duration // Minimum time between notifications about one service
+	Retries      int           // Attempts after a failed delivery
+	RetryBackoff time.Duration // Wait before the first retry, doubled on every retry
+	Timeout      time.Duration // Timeout of one attempt
+}
+
+// Notifier posts catalog events to webhook targets.
+//
+// Events are deduplicated: an event that repeats the last one sent about an
+// instance (same type and status) is not sent again. After a notification about
+// a service, the following events of that service are held for the cooldown;
+// only the latest event per instance is sent when it ends, so a flapping
+// instance sends at most one notification per cooldown.
+type Notifier struct {
+	targets []*target
+	options Options
+	client  *http.Client
+
+	mu        sync.Mutex
+	delivered map[string]string    // subject -> fingerprint of the last event sent
+	cooldowns map[string]*cooldown // service -> events held back
+}
+
+type target struct {
+	Target
+	payload *template.Template // nil sends the event as is
+	queue   chan catalog.Event
+}
+
+type cooldown struct {
+	pending map[string]catalog.Event // subject -> latest event
+}
+
+// New creates a notifier; it fails if a payload template does not parse
+func New(targets []Target, options Options) (*Notifier, error) {
+	notifier := &Notifier{
+		options:   options,
+		client:    &http.Client{Timeout: options.Timeout},
+		delivered: make(map[string]string),
+		cooldowns: make(map[string]*cooldown),
+	}
+
+	for _, t := range targets {
+		payload, err := parseTemplate(t.Template)
+		if err != nil {
+			return nil, err
+		}
+
+		notifier.targets = append(notifier.targets, &target{
+			Target:  t,
+			payload: payload,
+			queue:   make(chan catalog.Event, queueSize),
+		})
+	}
+
+	return notifier, nil
+}
+
+// Run delivers the events published by hub until ctx is done or hub is closed
+func (n *Notifier) Run(ctx context.Context, hub *catalog.Hub) {
+	for _, t := range n.targets {
+		go n.deliverQueue(ctx, t)
+	}
+
+	subscription := hub.Subscribe()
+	for {
+		lastID := n.consume(ctx, subscription)
+		if ctx.Err() != nil || !subscription.Dropped() {
+			return
+		}
+
+		// The notifier fell behind: catch up on the events it missed if they are still kept
+		missed, resumed, err := hub.SubscribeAfter(lastID)
+		if err != nil {
+			log.Printf("⚠️ Webhook notifier fell behind, some catalog events were not sent")
+			resumed = hub.Subscribe()
+		}
+
+		for _, event := range missed {
+			n.notify(event)
+		}
+		subscription = resumed
+	}
+}
+
+// consume notifies the events of a subscription until it is closed and
+// returns the id of the last event it handled
+func (n *Notifier) consume(ctx context.Context, subscription *catalog.Subscription) string {
+	defer subscription.Close()
+
+	lastID := subscription.After
+
+	for {
+		select {
+		case <-ctx.Done():
+			return lastID
+		case event, ok := <-subscription.C:
+			if !ok {
+				return lastID
+			}
+
+			n.notify(event)
+			lastID = event.ID
+		}
+	}
+}
+
+// notify sends an event right away, or holds it while its service is cooling down
+func (n *Notifier) notify(event catalog.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if held, ok := n.cooldowns[event.Service]; ok {
+		held.pending[subject(event)] = event
+		return
+	}
+
+	if n.send(event) {
+		n.startCooldown(event.Service)
+	}
+}
+
+// startCooldown holds back the events of a service for the cooldown
+func (n *Notifier) startCooldown(service string) {
+	if n.options.Cooldown <= 0 {
+		return
+	}
+
+	n.cooldowns[service] = &cooldown{pending: make(map[string]catalog.Event)}
+	time.AfterFunc(n.options.Cooldown, func() { n.endCooldown(service) })
+}
+
+// endCooldown sends the latest held event of every instance of a service
+func (n *Notifier) endCooldown(service string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	held := n.cooldowns[service]
+	delete(n.cooldowns, service)
+
+	events := make([]catalog.Event, 0, len(held.pending))
+	for _, event := range held.pending {
+		events = append(events, event)
+	}
+	slices.SortFunc(events, catalog.Event.Compare)
+
+	sent := false
+	for _, event := range events {
+		if n.send(event) {
+			sent = true
+		}
+	}
+
+	if sent {
+		n.startCooldown(service)
+	}
+}
+
+// send queues an event for every target that wants it, unless it is a duplicate.
+// It reports whether the event was sent.
+func (n *Notifier) send(event catalog.Event) bool {
+	key := subject(event)
+
+	fingerprint := fingerprint(event)
+	if n.delivered[key] == fingerprint {
+		log.Printf("🔕 Skipping duplicate %s event of %s", event.Type, key)
+		return false
+	}
+
+	switch event.Type {
+	case catalog.InstanceDeregistered, catalog.ServiceRemoved:
+		delete(n.delivered, key)
+	default:
+		n.delivered[key] = fingerprint
+	}
+
+	for _, t := range n.targets {
+		if !t.wants(event) {
+			continue
+		}
+
+		select {
+		case t.queue <- event:
+		default:
+			deliveries.Inc(t.URL, "dropped")
+			log.Printf("⚠️ Webhook queue of %s is full, dropping %s event of %s", t.URL, event.Type, key)
+		}
+	}
+
+	return true
+}
+
+// fingerprint identifies the state an event reports, so the same state is not sent twice.
+// Updates of an instance differ by its tags, meta, address and port.
+func fingerprint(event catalog.Event) string {
+	fingerprint := string(event.Type) + "/" + event.Status
+	if event.Type != catalog.InstanceUpdated {
+		return fingerprint
+	}
+
+	// Meta keys are marshalled sorted, so equal content hashes equally
+	content, _ := json.Marshal(struct {
+		Address string
+		Port    int
+		Tags    []string
+		Meta    map[string]string
+	}{event.Address, event.Port, event.Tags, event.Meta})
+
+	hash := fnv.New64a()
+	hash.Write(content)
+
+	return fmt.Sprintf("%s/%x", fingerprint, hash.Sum64())
+}
+
+// wants reports whether the target subscribed to an event
+func (t *target) wants(event catalog.Event) bool {
+	if len(t.Events) > 0 && !slices.Contains(t.Events, event.Type) {
+		return false
+	}
+
+	if len(t.Services) > 0 && !slices.Contains(t.Services, event.Service) {
+		return false
+	}
+
+	return true
+}
+
+// subject identifies what an event is about: an instance, or a service for service events
+func subject(event catalog.Event) string {
+	if event.Instance == "" {
+		return event.Service
+	}
+
+	return event.Service + "/" + event.Node + "/" + event.Instance
+}
@@ -12,8 +12,9 @@ func main() {
 	flag.Parse()
 
 	cmds := map[string]func(){
-		"help":  help,
-		"start": start,
+		"help":   help,
+		"start":  start,
+		"config": configCommand,
 	}
 
 	if cmdFunc, ok := cmds[flag.Arg(0)]; ok {
@@ -34,8 +35,18 @@ func help() {
 			fmt.Sprintf(header, "Usage", "Description") +
 			fmt.Sprintf(divider, strings.Repeat("-", 30), strings.Repeat("-", 50)) +
 			fmt.Sprintf(row, "help", "show this help message") +
-			fmt.Sprintf(row, "start", "start the server") +
+			fmt.Sprintf(row, "start [flags]", "start the server (see start -h for flags)") +
+			fmt.Sprintf(row, "config validate [path]", "validate and print the effective configuration") +
 			fmt.Sprintf(divider, strings.Repeat("_", 30), strings.Repeat("_", 50))
 
 	fmt.Fprintln(os.Stderr, output)
 }
+
+// subcommandArgs returns the command line arguments following the subcommand name
+func subcommandArgs() []string {
+	if flag.NArg() < 2 {
+		return nil
+	}
+
+	return flag.Args()[1:]
+}
@@ -5,21 +5,17 @@ import (
 	"log"
 
 	"service-a/util/config"
+	"service-a/util/load"
 
 	"github.com/hashicorp/consul/api"
 )
 
-// consulRegistration registers this service instance with Consul
-func consulRegistration(config config.Config) error {
-	// Create Consul client configuration
-	consulConfig := api.DefaultConfig()
-	consulConfig.Address = fmt.Sprintf("%s:%d", config.Consul.Host, config.Consul.Port)
-	consulConfig.Scheme = config.Consul.Scheme
-
+// consulRegistration registers this service instance with Consul and returns its service ID
+func consulRegistration(config config.Config) (string, error) {
 	// Create Consul client
-	client, err := api.NewClient(consulConfig)
+	client, err := newConsulClient(config.Consul)
 	if err != nil {
-		return fmt.Errorf("failed to create consul client: %w", err)
+		return "", err
 	}
 
 	// Determine the registration address
@@ -36,6 +32,34 @@ func consulRegistration(config config.Config) error {
 		healthCheckAddr = registerAddr
 	}
 
+	// Services serving TLS advertise it through the "protocol" meta key,
+	// so the API Gateway knows to call them over https
+	protocol := "http"
+	if config.TLS.Enabled {
+		protocol = "https"
+	}
+
+	// Health Check: Consul will periodically hit this endpoint
+	// If it returns non-200, Consul marks this instance as unhealthy
+	// Unhealthy instances are excluded from service discovery results
+	check := &api.AgentServiceCheck{
+		HTTP:                           fmt.Sprintf("%s://%s:%d/ping", protocol, healthCheckAddr, config.App.Port),
+		Interval:                       "10s", // Check every 10 seconds
+		Timeout:                        "3s",  // Timeout after 3 seconds
+		DeregisterCriticalServiceAfter: "30s", // Remove from registry if unhealthy for 30s
+	}
+
+	if config.TLS.Enabled {
+		// Consul may not trust our CA, the check only cares about liveness
+		check.TLSSkipVerify = true
+
+		// With mTLS Consul cannot present a client certificate, fall back to a TCP check
+		if config.TLS.ClientCAFile != "" {
+			check.HTTP = ""
+			check.TCP = fmt.Sprintf("%s:%d", healthCheckAddr, config.App.Port)
+		}
+	}
+
 	// Create service registration object
 	// This is the structured data Consul stores about our service
 	registration := &api.AgentServiceRegistration{
@@ -55,29 +79,41 @@ func consulRegistration(config config.Config) error {
 		// Example: API Gateway could search for services with tag "api"
 		Tags: []string{"api", "rest", "microservice"},
 
-		// Health Check: Consul will periodically hit this endpoint
-		// If it returns non-200, Consul marks this instance as unhealthy
-		// Unhealthy instances are excluded from service discovery results
-		Check: &api.AgentServiceCheck{
-			HTTP:                           fmt.Sprintf("http://%s:%d/ping", healthCheckAddr, config.App.Port),
-			Interval:                       "10s", // Check every 10 seconds
-			Timeout:                        "3s",  // Timeout after 3 seconds
-			DeregisterCriticalServiceAfter: "30s", // Remove from registry if unhealthy for 30s
+		// Health Check (see above)
+		Check: check,
+
+		// Weights: relative share of traffic for weighted load balancing,
+		// depending on whether the health check is passing or warning
+		Weights: &api.AgentWeights{
+			Passing: config.App.Weights.Passing,
+			Warning: config.App.Weights.Warning,
 		},
 
 		// Meta: Additional key-value metadata
 		Meta: map[string]string{
 			"version":     "1.0.0",
 			"environment": "development",
-			"protocol":    "http",
+			"protocol":    protocol,
 		},
 	}
 
+	// Load report: a TTL check the instance keeps alive with its current load as output
+	// It turns critical when the reports stop for three intervals
+	if config.LoadReport.Enabled {
+		registration.Checks = api.AgentServiceChecks{{
+			CheckID: loadCheckID(registration.ID),
+			Name:    "Load report",
+			Notes:   "Output carries load=<0..1>, read by the API Gateway's weighted balancer",
+			TTL:     (3 * config.LoadReport.Interval).String(),
+			Status:  api.HealthPassing,
+		}}
+	}
+
 	// Register the service with Consul
 	// After this call, other services can discover this service by querying Consul
 	err = client.Agent().ServiceRegister(registration)
 	if err != nil {
-		return fmt.Errorf("failed to register service with consul: %w", err)
+		return "", fmt.Errorf("failed to register service with consul: %w", err)
 	}
 
 	log.Printf("✅ Service '%s' successfully registered with Consul", config.App.Name)
@@ -85,8 +121,65 @@ func consulRegistration(config config.Config) error {
 	log.Printf("   - Bind Address: %s:%d (where service listens)", config.App.Host, config.App.Port)
 	log.Printf("   - Register Address: %s:%d (where others can reach it)", registration.Address, registration.Port)
 	log.Printf("   - Health Check Address: %s:%d (where Consul checks health)", healthCheckAddr, config.App.Port)
-	log.Printf("   - Health Check URL: %s", registration.Check.HTTP)
+	log.Printf("   - Health Check: %s%s", registration.Check.HTTP, registration.Check.TCP)
 	log.Printf("   - Tags: %v", registration.Tags)
+	log.Printf("   - Weights: passing %d, warning %d", registration.Weights.Passing, registration.Weights.Warning)
+	if config.LoadReport.Enabled {
+		log.Printf("   - Load report: every %s (TTL check %s)", config.LoadReport.Interval, loadCheckID(registration.ID))
+	}
+
+	return registration.ID, nil
+}
+
+// loadCheckID is the ID of the TTL check carrying the load of an instance
+// The API Gateway finds the check by its ":load" suffix
+func loadCheckID(serviceID string) string {
+	return fmt.Sprintf("service:%s:load", serviceID)
+}
+
+// reportLoad keeps the load check of this instance up to date, forever
+// The check stays passing: the load shifts traffic through the gateway's weights,
+// a warning would drop the instance from passing-only routes at once
+func reportLoad(config config.Config, serviceID string, tracker *load.Tracker) {
+	client, err := newConsulClient(config.Consul)
+	if err != nil {
+		log.Printf("⚠️ Load reporting disabled: %v", err)
+		return
+	}
+
+	limits := load.Limits{
+		MaxInFlight: config.LoadReport.MaxInFlight,
+		MaxLatency:  config.LoadReport.MaxLatency,
+	}
+
+	load.Run(tracker, limits, config.LoadReport.Interval, func(output string) error {
+		return client.Agent().UpdateTTL(loadCheckID(serviceID), output, api.HealthPassing)
+	})
+}
+
+// newConsulClient creates a Consul API client, including the ACL token and TLS settings
+func newConsulClient(config config.Consul) (*api.Client, error) {
+	consulConfig := api.DefaultConfig()
+	consulConfig.Address = fmt.Sprintf("%s:%d", config.Host, config.Port)
+	consulConfig.Scheme = config.Scheme
+
+	// ACL token
+	consulConfig.Token = config.Token
+	consulConfig.TokenFile = config.TokenFile
+
+	// TLS
+	consulConfig.TLSConfig = api.TLSConfig{
+		Address:            config.TLSServerName,
+		CAFile:             config.CAFile,
+		CertFile:           config.CertFile,
+		KeyFile:            config.KeyFile,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	client, err := api.NewClient(consulConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul client: %w", err)
+	}
 
-	return nil
+	return client, nil
 }
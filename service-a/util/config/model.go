@@ -1,19 +1,62 @@
 package config
 
+import "time"
+
 // App config
 
 type App struct {
-	Name               string `mapstructure:"name"`
-	Host               string `mapstructure:"host"` // Bind address (0.0.0.0 for listening)
-	Port               int    `mapstructure:"port"`
-	RegisterAddress    string `mapstructure:"register_address"`     // Address for service registration
-	HealthCheckAddress string `mapstructure:"health_check_address"` // Address for Consul health checks
+	Name               string `mapstructure:"name" json:"name"`
+	Host               string `mapstructure:"host" json:"host"` // Bind address (0.0.0.0 for listening)
+	Port               int    `mapstructure:"port" json:"port"`
+	RegisterAddress    string `mapstructure:"register_address" json:"register_address"`         // Address for service registration
+	HealthCheckAddress string `mapstructure:"health_check_address" json:"health_check_address"` // Address for Consul health checks
+
+	// Share of traffic this instance receives from weighted balancers
+	Weights Weights `mapstructure:"weights" json:"weights"`
+}
+
+// Weights config
+// Relative amount of traffic while the health check is passing or warning
+
+type Weights struct {
+	Passing int `mapstructure:"passing" json:"passing"`
+	Warning int `mapstructure:"warning" json:"warning"`
 }
 
 // Consul config
 
 type Consul struct {
-	Host   string `mapstructure:"host"`
-	Port   int    `mapstructure:"port"`
-	Scheme string `mapstructure:"scheme"`
+	Host   string `mapstructure:"host" json:"host"`
+	Port   int    `mapstructure:"port" json:"port"`
+	Scheme string `mapstructure:"scheme" json:"scheme"`
+
+	// ACL token sent with every Consul request, given inline or read from token_file
+	Token     string `mapstructure:"token" json:"token"`
+	TokenFile string `mapstructure:"token_file" json:"token_file"`
+
+	// TLS settings for talking to a Consul HTTPS listener (requires scheme "https")
+	CAFile             string `mapstructure:"ca_file" json:"ca_file"`                           // CA bundle used to verify the Consul server
+	CertFile           string `mapstructure:"cert_file" json:"cert_file"`                       // Client certificate presented to Consul
+	KeyFile            string `mapstructure:"key_file" json:"key_file"`                         // Private key of the client certificate
+	TLSServerName      string `mapstructure:"tls_server_name" json:"tls_server_name"`           // Server name expected in the Consul certificate
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify" json:"insecure_skip_verify"` // Disable Consul certificate verification (development only)
+}
+
+// TLS config
+
+type TLS struct {
+	Enabled      bool   `mapstructure:"enabled" json:"enabled"`               // Serve HTTPS instead of HTTP
+	CertFile     string `mapstructure:"cert_file" json:"cert_file"`           // Server certificate (reloaded when it changes)
+	KeyFile      string `mapstructure:"key_file" json:"key_file"`             // Private key of the server certificate
+	ClientCAFile string `mapstructure:"client_ca_file" json:"client_ca_file"` // When set, clients must present a certificate signed by this CA (mTLS)
+}
+
+// LoadReport config
+// The instance reports its load through a TTL check, for the gateway to shift traffic away when busy
+
+type LoadReport struct {
+	Enabled     bool          `mapstructure:"enabled" json:"enabled"`
+	Interval    time.Duration `mapstructure:"interval" json:"interval"`           // How often the load is reported
+	MaxInFlight int           `mapstructure:"max_in_flight" json:"max_in_flight"` // Requests in flight at which the instance counts as fully loaded
+	MaxLatency  time.Duration `mapstructure:"max_latency" json:"max_latency"`     // Average latency at which the instance counts as fully loaded
 }
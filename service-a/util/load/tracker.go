@@ -0,0 +1,73 @@
+package load
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Tracker measures how busy this instance is: requests in flight,
+// CPU used by the process and the latency of finished requests
+type Tracker struct {
+	inFlight atomic.Int64
+
+	mu        sync.Mutex
+	finished  int64         // Requests finished since the previous sample
+	latency   time.Duration // Total latency of those requests
+	cpuTime   time.Duration // CPU time of the process at the previous sample
+	sampledAt time.Time
+}
+
+// Sample is the load of the instance since the previous sample
+type Sample struct {
+	InFlight int64
+	CPU      float64       // Share of all CPUs used by the process, between 0 and 1
+	Latency  time.Duration // Average latency of the requests finished in between, 0 without requests
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		cpuTime:   processCPUTime(),
+		sampledAt: time.Now(),
+	}
+}
+
+// Start records the start of a request. The returned func records its end.
+func (t *Tracker) Start() func() {
+	started := time.Now()
+	t.inFlight.Add(1)
+
+	return func() {
+		t.inFlight.Add(-1)
+
+		t.mu.Lock()
+		t.finished++
+		t.latency += time.Since(started)
+		t.mu.Unlock()
+	}
+}
+
+// Sample measures the load since the previous call
+func (t *Tracker) Sample() Sample {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	cpuTime := processCPUTime()
+
+	sample := Sample{InFlight: t.inFlight.Load()}
+
+	if elapsed := now.Sub(t.sampledAt); elapsed > 0 {
+		sample.CPU = min(float64(cpuTime-t.cpuTime)/float64(elapsed)/float64(runtime.NumCPU()), 1)
+	}
+
+	if t.finished > 0 {
+		sample.Latency = t.latency / time.Duration(t.finished)
+	}
+
+	t.finished, t.latency = 0, 0
+	t.cpuTime, t.sampledAt = cpuTime, now
+
+	return sample
+}
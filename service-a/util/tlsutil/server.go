@@ -0,0 +1,31 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+)
+
+// ServerConfig returns a TLS server configuration that always serves the
+// most recently loaded certificate. When a CA file is configured, clients
+// must present a certificate signed by it (mutual TLS).
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+
+		// Build the config per handshake so reloaded files take effect immediately
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			loaded := r.current.Load()
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*loaded.certificate},
+			}
+
+			if loaded.caPool != nil {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = loaded.caPool
+			}
+
+			return config, nil
+		},
+	}
+}
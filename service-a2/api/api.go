@@ -2,21 +2,29 @@ package api
 
 import (
 	"service-a2/middleware"
+	"service-a2/util/load"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type Api struct {
 	serviceName string
+	loadTracker *load.Tracker // nil when load reporting is disabled
 }
 
-func NewApi(serviceName string) *Api {
+func NewApi(serviceName string, loadTracker *load.Tracker) *Api {
 	return &Api{
 		serviceName: serviceName,
+		loadTracker: loadTracker,
 	}
 }
 
 func (api *Api) DefineEndpoints(app *fiber.App) *fiber.App {
+	// Load tracking middleware, measures every request including the error handling
+	if api.loadTracker != nil {
+		app.Use(middleware.TrackLoad(api.loadTracker))
+	}
+
 	// Error handler middleware
 	app.Use(middleware.ErrorHandler())
 
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"service-a2/util/config"
+)
+
+// tlsConsul is a fake Consul HTTPS listener that requires a client certificate
+type tlsConsul struct {
+	server *httptest.Server
+	host   string
+	port   int
+
+	caFile, certFile, keyFile string // Trusted CA and a client certificate signed by it
+
+	mu          sync.Mutex
+	token       string // X-Consul-Token of the last request
+	clientNames []string
+}
+
+// consulServerName is the only name in the fake Consul's certificate
+const consulServerName = "consul.test"
+
+func newTLSConsul(t *testing.T) *tlsConsul {
+	t.Helper()
+
+	dir := t.TempDir()
+	ca, caKey := newCertificate(t, nil, nil, x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	serverCert, serverKey := newCertificate(t, ca, caKey, x509.Certificate{
+		Subject:     pkix.Name{CommonName: consulServerName},
+		DNSNames:    []string{consulServerName},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientCert, clientKey := newCertificate(t, ca, caKey, x509.Certificate{
+		Subject:     pkix.Name{CommonName: "service-a2"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	fake := &tlsConsul{
+		caFile:   writePEM(t, dir, "ca.pem", "CERTIFICATE", ca.Raw),
+		certFile: writePEM(t, dir, "client.pem", "CERTIFICATE", clientCert.Raw),
+		keyFile:  writeKey(t, dir, "client-key.pem", clientKey),
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	fake.server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.token = r.Header.Get("X-Consul-Token")
+		for _, cert := range r.TLS.PeerCertificates {
+			fake.clientNames = append(fake.clientNames, cert.Subject.CommonName)
+		}
+		fake.mu.Unlock()
+
+		if r.URL.Path != "/v1/status/leader" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`"10.0.0.1:8300"`))
+	}))
+	fake.server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	fake.server.Config.ErrorLog = log.New(io.Discard, "", 0) // Rejected handshakes are expected
+	fake.server.StartTLS()
+	t.Cleanup(fake.server.Close)
+
+	host, port, _ := net.SplitHostPort(fake.server.Listener.Addr().String())
+	fake.host = host
+	fake.port, _ = strconv.Atoi(port)
+
+	return fake
+}
+
+// config returns a Consul config that trusts the fake's CA and presents its client certificate
+func (f *tlsConsul) config() config.Consul {
+	return config.Consul{
+		Host:          f.host,
+		Port:          f.port,
+		Scheme:        "https",
+		CAFile:        f.caFile,
+		CertFile:      f.certFile,
+		KeyFile:       f.keyFile,
+		TLSServerName: consulServerName,
+	}
+}
+
+func (f *tlsConsul) lastRequest() (token string, clientNames []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.token, f.clientNames
+}
+
+func TestNewConsulClientTLS(t *testing.T) {
+	fake := newTLSConsul(t)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		configure func(*config.Consul)
+		wantErr   string // Empty when the request must succeed
+		wantToken string
+	}{
+		{
+			name:      "inline token, CA, client certificate and server name",
+			configure: func(c *config.Consul) { c.Token = "inline-token" },
+			wantToken: "inline-token",
+		},
+		{
+			name:      "token file",
+			configure: func(c *config.Consul) { c.TokenFile = tokenFile },
+			wantToken: "file-token",
+		},
+		{
+			name:      "insecure skip verify accepts an untrusted certificate",
+			configure: func(c *config.Consul) { c.CAFile, c.TLSServerName, c.InsecureSkipVerify = "", "", true },
+		},
+		{
+			name:      "untrusted CA",
+			configure: func(c *config.Consul) { c.CAFile = "" },
+			wantErr:   "certificate signed by unknown authority",
+		},
+		{
+			name:      "certificate not valid for the address without tls_server_name",
+			configure: func(c *config.Consul) { c.TLSServerName = "" },
+			wantErr:   "doesn't contain any IP SANs",
+		},
+		{
+			name:      "wrong tls_server_name",
+			configure: func(c *config.Consul) { c.TLSServerName = "other.test" },
+			wantErr:   "certificate is valid for " + consulServerName,
+		},
+		{
+			name:      "no client certificate",
+			configure: func(c *config.Consul) { c.CertFile, c.KeyFile = "", "" },
+			wantErr:   "certificate required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			consulConfig := fake.config()
+			test.configure(&consulConfig)
+
+			client, err := newConsulClient(consulConfig)
+			if err != nil {
+				t.Fatalf("newConsulClient: %v", err)
+			}
+
+			leader, err := client.Status().Leader()
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Leader() error = %v, want it to contain %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Leader(): %v", err)
+			}
+			if leader != "10.0.0.1:8300" {
+				t.Errorf("Leader() = %q, want 10.0.0.1:8300", leader)
+			}
+
+			token, clientNames := fake.lastRequest()
+			if token != test.wantToken {
+				t.Errorf("X-Consul-Token = %q, want %q", token, test.wantToken)
+			}
+			if len(clientNames) == 0 || clientNames[len(clientNames)-1] != "service-a2" {
+				t.Errorf("client certificates = %v, want the service-a2 certificate", clientNames)
+			}
+		})
+	}
+}
+
+// newCertificate creates a certificate from template, signed by parent
+// (self-signed when parent is nil)
+func newCertificate(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, template x509.Certificate) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	if parent == nil {
+		parent, parentKey = &template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func writeKey(t *testing.T, dir, name string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return writePEM(t, dir, name, "EC PRIVATE KEY", der)
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"service-a2/util/load"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TrackLoad records every request in the load tracker
+func TrackLoad(tracker *load.Tracker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		done := tracker.Start()
+		defer done()
+
+		return c.Next()
+	}
+}
@@ -1,50 +1,199 @@
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"log"
 	"os"
+	"reflect"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	App    App    `mapstructure:"app"`
-	Consul Consul `mapstructure:"consul"`
+	App        App        `mapstructure:"app" json:"app"`
+	Consul     Consul     `mapstructure:"consul" json:"consul"`
+	TLS        TLS        `mapstructure:"tls" json:"tls"`
+	LoadReport LoadReport `mapstructure:"load_report" json:"load_report"`
 }
 
-// LoadConfig reads configuration from file or environment variables.
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("config")
-	viper.SetConfigType("json")
+// redactedValue replaces secrets when the configuration is printed
+const redactedValue = "**redacted**"
 
-	// Enable automatic environment variable reading
-	viper.AutomaticEnv()
+// defaults are applied before any config file, environment variable or flag
+var defaults = map[string]interface{}{
+	"app.host":            "0.0.0.0",
+	"app.weights.passing": 1,
+	"app.weights.warning": 1,
+	"consul.host":         "localhost",
+	"consul.port":         8500,
+	"consul.scheme":       "http",
 
-	// Set up environment variable mappings for nested config
-	viper.BindEnv("consul.host", "CONSUL_HOST")
-	viper.BindEnv("consul.port", "CONSUL_PORT")
-	viper.BindEnv("consul.scheme", "CONSUL_SCHEME")
+	"load_report.interval":      "5s",
+	"load_report.max_in_flight": 50,
+	"load_report.max_latency":   "1s",
+}
+
+// Flags holds the command line overrides for the configuration
+type Flags struct {
+	// Path is a config file or a directory containing config.{json,yaml,yml,toml}
+	Path string
+
+	values map[string]*string
+}
+
+// RegisterFlags registers --config plus one flag per configuration key on fs.
+// Flag names are derived from the keys: "app.port" becomes --port,
+// "consul.host" becomes --consul-host.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{values: make(map[string]*string)}
+
+	fs.StringVar(&flags.Path, "config", "", "config file, or directory containing config.{json,yaml,yml,toml} (default \".\")")
+
+	for _, key := range configKeys() {
+		flags.values[key] = fs.String(FlagName(key), "", fmt.Sprintf("overrides %s (env %s)", key, EnvName(key)))
+	}
+
+	return flags
+}
+
+// FlagName returns the command line flag name of a configuration key
+func FlagName(key string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(strings.TrimPrefix(key, "app."))
+}
+
+// envPrefix starts the environment variable names of this binary,
+// so they do not collide with the variables of other tools
+const envPrefix = "SERVICE_A2_"
 
-	err = viper.ReadInConfig()
+// EnvName returns the environment variable name of a configuration key:
+// "consul.host" becomes SERVICE_A2_CONSUL_HOST
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// LoadConfig reads and validates the configuration.
+//
+// Values are resolved with the following precedence (highest first):
+//  1. command line flags (--port, --consul-host, ...)
+//  2. environment variables (SERVICE_A2_APP_PORT, SERVICE_A2_CONSUL_HOST, ...)
+//  3. the config file (config.json, config.yaml, config.yml or config.toml)
+//  4. built-in defaults
+//
+// The config file is optional unless it is explicitly set with --config,
+// so a container can be configured with environment variables alone.
+func LoadConfig(flags *Flags) (config Config, err error) {
+	config, err = ReadConfig(flags)
 	if err != nil {
-		return config, fmt.Errorf("failed to read configuration file: %s", err)
+		return config, err
 	}
 
-	err = viper.Unmarshal(&config)
+	err = config.Validate()
 	if err != nil {
-		return config, fmt.Errorf("failed to unmarshal configuration: %s", err)
+		return config, err
+	}
+
+	log.Printf("🔧 Consul configuration: %s://%s:%d", config.Consul.Scheme, config.Consul.Host, config.Consul.Port)
+
+	return
+}
+
+// ReadConfig reads the effective configuration without validating it
+func ReadConfig(flags *Flags) (config Config, err error) {
+	if flags == nil {
+		flags = &Flags{}
+	}
+
+	v := viper.New()
+
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	// Every configuration key can be set from the environment
+	for _, key := range configKeys() {
+		v.BindEnv(key, EnvName(key))
+	}
+
+	// Only flags that were actually given override the other sources
+	for key, value := range flags.values {
+		if *value != "" {
+			v.Set(key, *value)
+		}
 	}
 
-	// Log the final Consul configuration for debugging
-	consulHost := config.Consul.Host
-	if envHost := os.Getenv("CONSUL_HOST"); envHost != "" {
-		consulHost = envHost
-		config.Consul.Host = envHost
+	explicitFile := false
+	if info, statErr := os.Stat(flags.Path); statErr == nil && !info.IsDir() {
+		// The file format is taken from the extension
+		v.SetConfigFile(flags.Path)
+		explicitFile = true
+	} else {
+		path := flags.Path
+		if path == "" {
+			path = "."
+		}
+
+		v.AddConfigPath(path)
+		v.SetConfigName("config")
+		explicitFile = flags.Path != ""
 	}
 
-	fmt.Printf("🔧 Consul configuration: %s://%s:%d\n", config.Consul.Scheme, consulHost, config.Consul.Port)
+	err = v.ReadInConfig()
+	if err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if explicitFile || !errors.As(err, &notFound) {
+			return config, fmt.Errorf("failed to read configuration file: %s", err)
+		}
+
+		log.Printf("🔧 No config file found, using environment variables and defaults")
+	}
+
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return config, fmt.Errorf("failed to unmarshal configuration: %s", err)
+	}
 
 	return
 }
+
+// Redacted returns a copy of the configuration that is safe to print
+func (c Config) Redacted() Config {
+	redacted := c
+
+	if c.Consul.Token != "" {
+		redacted.Consul.Token = redactedValue
+	}
+
+	return redacted
+}
+
+// configKeys returns every leaf configuration key, e.g. "app.port"
+func configKeys() []string {
+	return collectKeys(reflect.TypeOf(Config{}), "")
+}
+
+func collectKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, collectKeys(field.Type, key+".")...)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
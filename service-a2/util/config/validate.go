@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// hostnamePattern matches RFC 1123 host names such as "consul" or "service-a.internal"
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// FieldError describes why a single configuration field is invalid
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists every configuration field that failed validation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field.Field, field.Message))
+	}
+
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(messages, "; "))
+}
+
+// validator collects field errors while checking a configuration
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) fail(field, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.fail(field, "is required")
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) port(field string, value int) {
+	if value < 1 || value > 65535 {
+		v.fail(field, "must be between 1 and 65535, got %d", value)
+	}
+}
+
+func (v *validator) host(field, value string) {
+	if net.ParseIP(value) == nil && !hostnamePattern.MatchString(value) {
+		v.fail(field, "must be an IP address or host name, got %q", value)
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+
+	v.fail(field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// file checks that an optional file setting points to a readable file
+func (v *validator) file(field, path string) {
+	if path == "" {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		v.fail(field, "cannot read %q: %v", path, err)
+		return
+	}
+
+	if info.IsDir() {
+		v.fail(field, "must be a file, %q is a directory", path)
+	}
+}
+
+// Validate checks the configuration and reports every invalid field at once
+func (c Config) Validate() error {
+	v := &validator{}
+
+	c.App.validate(v)
+	c.Consul.validate(v)
+	c.TLS.validate(v)
+	c.LoadReport.validate(v)
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Fields: v.errors}
+	}
+
+	return nil
+}
+
+func (a App) validate(v *validator) {
+	v.required("app.name", a.Name)
+	if v.required("app.host", a.Host) {
+		v.host("app.host", a.Host)
+	}
+	v.port("app.port", a.Port)
+
+	// Registration addresses are optional, they fall back to the bind host
+	if a.RegisterAddress != "" {
+		v.host("app.register_address", a.RegisterAddress)
+	}
+	if a.HealthCheckAddress != "" {
+		v.host("app.health_check_address", a.HealthCheckAddress)
+	}
+
+	// Consul rejects a passing weight below 1
+	if a.Weights.Passing < 1 {
+		v.fail("app.weights.passing", "must be at least 1, got %d", a.Weights.Passing)
+	}
+	if a.Weights.Warning < 0 {
+		v.fail("app.weights.warning", "must not be negative, got %d", a.Weights.Warning)
+	}
+}
+
+func (c Consul) validate(v *validator) {
+	if v.required("consul.host", c.Host) {
+		v.host("consul.host", c.Host)
+	}
+	v.port("consul.port", c.Port)
+	v.oneOf("consul.scheme", c.Scheme, "http", "https")
+
+	if c.Token != "" && c.TokenFile != "" {
+		v.fail("consul.token_file", "must not be set together with consul.token")
+	}
+	v.file("consul.token_file", c.TokenFile)
+
+	v.file("consul.ca_file", c.CAFile)
+	v.file("consul.cert_file", c.CertFile)
+	v.file("consul.key_file", c.KeyFile)
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		v.fail("consul.cert_file", "must be set together with consul.key_file")
+	}
+
+	usesTLS := c.CAFile != "" || c.CertFile != "" || c.TLSServerName != "" || c.InsecureSkipVerify
+	if usesTLS && c.Scheme != "https" {
+		v.fail("consul.scheme", "must be \"https\" when TLS settings are configured, got %q", c.Scheme)
+	}
+}
+
+func (t TLS) validate(v *validator) {
+	if !t.Enabled {
+		return
+	}
+
+	if v.required("tls.cert_file", t.CertFile) {
+		v.file("tls.cert_file", t.CertFile)
+	}
+	if v.required("tls.key_file", t.KeyFile) {
+		v.file("tls.key_file", t.KeyFile)
+	}
+	v.file("tls.client_ca_file", t.ClientCAFile)
+}
+
+func (l LoadReport) validate(v *validator) {
+	if !l.Enabled {
+		return
+	}
+
+	if l.Interval < time.Second {
+		v.fail("load_report.interval", "must be at least 1s, got %s", l.Interval)
+	}
+	if l.MaxInFlight < 1 {
+		v.fail("load_report.max_in_flight", "must be at least 1, got %d", l.MaxInFlight)
+	}
+	if l.MaxLatency <= 0 {
+		v.fail("load_report.max_latency", "must be positive, got %s", l.MaxLatency)
+	}
+}
@@ -0,0 +1,55 @@
+package load
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Limits at which the instance counts as fully loaded
+type Limits struct {
+	MaxInFlight int           // Requests in flight
+	MaxLatency  time.Duration // Average request latency
+}
+
+// Load combines a sample into one value between 0 (idle) and 1 (fully loaded):
+// the highest of the in-flight, CPU and latency ratios
+func (s Sample) Load(limits Limits) float64 {
+	load := s.CPU
+
+	if limits.MaxInFlight > 0 {
+		load = max(load, float64(s.InFlight)/float64(limits.MaxInFlight))
+	}
+
+	if limits.MaxLatency > 0 {
+		load = max(load, float64(s.Latency)/float64(limits.MaxLatency))
+	}
+
+	return min(load, 1)
+}
+
+// Output formats a sample as the output of the load check. The gateway reads the
+// "load" field; the others explain it to operators.
+func (s Sample) Output(load float64) string {
+	return fmt.Sprintf("load=%.2f in_flight=%d cpu=%.2f latency_ms=%.1f",
+		load, s.InFlight, s.CPU, float64(s.Latency)/float64(time.Millisecond))
+}
+
+// Run samples the tracker every interval and reports its load output, forever
+func Run(tracker *Tracker, limits Limits, interval time.Duration, report func(output string) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failing := false
+	for range ticker.C {
+		sample := tracker.Sample()
+
+		err := report(sample.Output(sample.Load(limits)))
+		if err != nil && !failing {
+			log.Printf("⚠️ Failed to report load: %v", err)
+		} else if err == nil && failing {
+			log.Printf("✅ Reporting load again")
+		}
+		failing = err != nil
+	}
+}
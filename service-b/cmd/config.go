@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"service-b/util/config"
+)
+
+// configCommand dispatches the "config" subcommands
+func configCommand() {
+	if flag.Arg(1) != "validate" {
+		fmt.Fprintln(os.Stderr, "Usage: config validate [path] [flags]")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+	configFlags := config.RegisterFlags(fs)
+
+	args := flag.Args()[2:]
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		configFlags.Path = args[0]
+		args = args[1:]
+	}
+	fs.Parse(args)
+
+	configValidate(configFlags)
+}
+
+// configValidate prints the effective configuration (file merged with
+// environment and flag overrides, secrets redacted) and validates it
+func configValidate(configFlags *config.Flags) {
+	cfg, err := config.ReadConfig(configFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	output, err := json.MarshalIndent(cfg.Redacted(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ failed to encode configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(output))
+
+	err = cfg.Validate()
+
+	var validationErr *config.ValidationError
+	if errors.As(err, &validationErr) {
+		fmt.Fprintln(os.Stderr, "❌ configuration is invalid:")
+		for _, field := range validationErr.Fields {
+			fmt.Fprintf(os.Stderr, "   - %s: %s\n", field.Field, field.Message)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(os.Stderr, "✅ configuration is valid")
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"os"
 
 	"service-b/api"
@@ -11,7 +13,8 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func runRestServer(port int, api *api.Api) {
+// runRestServer serves the API over plain HTTP, or over (m)TLS when tlsConfig is set
+func runRestServer(port int, tlsConfig *tls.Config, api *api.Api) {
 	// Init fiber app
 	app := fiber.New()
 
@@ -27,7 +30,14 @@ func runRestServer(port int, api *api.Api) {
 	app = api.DefineEndpoints(app)
 
 	// start the server
-	err := app.Listen(fmt.Sprintf(":%d", port))
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err == nil {
+		if tlsConfig != nil {
+			ln = tls.NewListener(ln, tlsConfig)
+		}
+
+		err = app.Listener(ln)
+	}
 	if err != nil {
 		log.Printf("failed to listen at port: %v!", port)
 
@@ -1,17 +1,26 @@
 package main
 
 import (
+	"crypto/tls"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
 
 	"service-b/api"
 	"service-b/util/config"
+	"service-b/util/load"
+	"service-b/util/tlsutil"
 )
 
 func start() {
-	// Load environment variables from .env file
-	config, err := config.LoadConfig(".")
+	// Parse the command line overrides of the configuration
+	fs := flag.NewFlagSet("start", flag.ExitOnError)
+	configFlags := config.RegisterFlags(fs)
+	fs.Parse(subcommandArgs())
+
+	// Load configuration from flags, environment variables and config file
+	config, err := config.LoadConfig(configFlags)
 	if err != nil {
 		log.Printf("failed to load config: %v", err)
 
@@ -22,18 +31,42 @@ func start() {
 	log.Printf("Starting %s service ...", config.App.Name)
 
 	// consul registration
-	err = consulRegistration(config)
+	serviceID, err := consulRegistration(config)
 	if err != nil {
 		log.Printf("failed to register service: %v", err)
 
 		os.Exit(1)
 	}
 
+	// Track in-flight requests, CPU and latency, and report the load to Consul
+	var tracker *load.Tracker
+	if config.LoadReport.Enabled {
+		tracker = load.NewTracker()
+		go reportLoad(config, serviceID, tracker)
+	}
+
 	// Init api layer
-	restApi := api.NewApi(config.App.Name)
+	restApi := api.NewApi(config.App.Name, tracker)
+
+	// Load TLS certificates (reloaded automatically when the files change)
+	var tlsConfig *tls.Config
+	if config.TLS.Enabled {
+		reloader, err := tlsutil.NewReloader(tlsutil.Files{
+			CertFile: config.TLS.CertFile,
+			KeyFile:  config.TLS.KeyFile,
+			CAFile:   config.TLS.ClientCAFile,
+		})
+		if err != nil {
+			log.Printf("failed to load TLS certificates: %v", err)
+
+			os.Exit(1)
+		}
+
+		tlsConfig = reloader.ServerConfig()
+	}
 
 	// Run rest server
-	runRestServer(config.App.Port, restApi)
+	runRestServer(config.App.Port, tlsConfig, restApi)
 
 	// wait for ctrl + c to exit
 	ch := make(chan os.Signal, 1)
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package load
+
+import "time"
+
+// processCPUTime is not measured on this platform, the CPU share is always reported as 0
+func processCPUTime() time.Duration {
+	return 0
+}
@@ -0,0 +1,136 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// reloadInterval is how often the certificate files are checked for changes
+const reloadInterval = 10 * time.Second
+
+// Files lists the PEM files a Reloader keeps loaded
+type Files struct {
+	CertFile string // Certificate presented to the peer
+	KeyFile  string // Private key of the certificate
+	CAFile   string // CA bundle used to verify the peer (optional)
+}
+
+// bundle is one consistent snapshot of the loaded files
+type bundle struct {
+	certificate *tls.Certificate
+	caPool      *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+// Reloader keeps a certificate and CA pool loaded from files
+// and reloads them whenever one of the files changes on disk
+type Reloader struct {
+	files   Files
+	current atomic.Pointer[bundle]
+}
+
+// NewReloader loads the files once and starts watching them for changes
+func NewReloader(files Files) (*Reloader, error) {
+	r := &Reloader{files: files}
+
+	loaded, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(loaded)
+
+	go r.watch()
+
+	return r, nil
+}
+
+// watch polls the files and swaps in a new bundle when they change
+// A broken update (e.g. a half-written key) keeps the previous bundle in use
+func (r *Reloader) watch() {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !r.changed() {
+			continue
+		}
+
+		loaded, err := r.load()
+		if err != nil {
+			log.Printf("❌ Failed to reload TLS files, keeping previous certificates: %v", err)
+			continue
+		}
+
+		r.current.Store(loaded)
+		log.Printf("🔐 TLS certificates reloaded from %s", r.files.CertFile)
+	}
+}
+
+// changed reports whether any file has a different modification time than the loaded bundle
+func (r *Reloader) changed() bool {
+	loaded := r.current.Load()
+
+	for _, path := range r.paths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if !info.ModTime().Equal(loaded.modTimes[path]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Reloader) paths() []string {
+	var paths []string
+	for _, path := range []string{r.files.CertFile, r.files.KeyFile, r.files.CAFile} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// load reads all files into a new bundle
+func (r *Reloader) load() (*bundle, error) {
+	loaded := &bundle{modTimes: make(map[string]time.Time)}
+
+	for _, path := range r.paths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		loaded.modTimes[path] = info.ModTime()
+	}
+
+	if r.files.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificate %s: %w", r.files.CertFile, err)
+		}
+		loaded.certificate = &certificate
+	}
+
+	if r.files.CAFile != "" {
+		pem, err := os.ReadFile(r.files.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", r.files.CAFile, err)
+		}
+
+		loaded.caPool = x509.NewCertPool()
+		if !loaded.caPool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", r.files.CAFile)
+		}
+	}
+
+	return loaded, nil
+}